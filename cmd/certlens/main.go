@@ -1,14 +1,22 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
+	"slices"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"k8s.io/klog/v2"
 
 	"github.com/codechamp1/certlens/configs"
+	"github.com/codechamp1/certlens/internal/cli"
 	"github.com/codechamp1/certlens/internal/client"
+	"github.com/codechamp1/certlens/internal/export"
 	"github.com/codechamp1/certlens/internal/repository"
 	"github.com/codechamp1/certlens/internal/service"
 	"github.com/codechamp1/certlens/internal/ui"
@@ -17,21 +25,81 @@ import (
 func main() {
 	config := configs.Load()
 
-	kubeClient, err := client.NewSecretsFetcher(config.KubeConfigPath, config.Context)
+	var opts []service.Option
+	if config.TrustBundle != "" {
+		roots, err := service.LoadTrustBundle(config.TrustBundle)
+		if err != nil {
+			log.Fatalf("Failed to load trust bundle: %v", err)
+		}
+		opts = append(opts, service.WithTrustBundle(roots))
+	}
+	expiry, err := expiryPolicy(config)
+	if err != nil {
+		log.Fatalf("Failed to load expiry thresholds: %v", err)
+	}
+	opts = append(opts, service.WithExpiryPolicy(expiry))
+	if config.Policy != "" {
+		rules, err := configs.LoadPolicy(config.Policy)
+		if err != nil {
+			log.Fatalf("Failed to load policy: %v", err)
+		}
+		opts = append(opts, service.WithLintRules(append(service.DefaultLintRules(), rules...)))
+	}
 
+	svc, err := newMultiClusterService(config, opts)
 	if err != nil {
 		log.Fatalf("Failed to create Kubernetes client: %v", err)
 	}
 
-	repo := repository.NewSecretsRepository(kubeClient)
+	// the commands stop their requests on ctrl+c, the TUI cancels its own
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-	svc := service.NewSecretsService(repo)
+	switch config.Command {
+	case configs.CommandCheck:
+		os.Exit(runCheck(ctx, svc, config))
+	case configs.CommandExport:
+		os.Exit(runExport(ctx, svc, config))
+	case configs.CommandProbe:
+		if config.Target == "" {
+			fmt.Fprintln(os.Stderr, "Error: -target is required")
+			os.Exit(cli.ExitError)
+		}
+		os.Exit(cli.RunProbe(ctx, svc, cli.ProbeOptions{
+			Namespace: config.Namespace,
+			Name:      config.Name,
+			Target:    config.Target,
+		}, os.Stdout, os.Stderr))
+	case configs.CommandServeMetrics:
+		os.Exit(cli.RunServeMetrics(ctx, svc, cli.ServeMetricsOptions{
+			Namespace:     config.Namespace,
+			ListenAddress: config.ListenAddress,
+			Interval:      time.Duration(config.RefreshInterval),
+		}, os.Stderr))
+	}
 
-	model, err := ui.NewModel(svc, config.Namespace, config.Name)
+	if config.Target != "" && config.Name == "" {
+		fmt.Fprintln(os.Stderr, "Error: -probe requires -name, the endpoint is compared with that secret")
+		os.Exit(cli.ExitError)
+	}
+	model, err := ui.NewModel(svc, config.Namespace, config.Name, config.Watch && !config.LocalFiles(), config.Target)
 
 	if err != nil {
 		log.Fatalf("Failed to create UI model: %v", err)
 	}
+	format, err := export.ParseFormat(config.Format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(cli.ExitError)
+	}
+	model = model.WithExport(config.ExportDir, format)
+	if !config.LocalFiles() {
+		model = model.WithSwitcher(switcher{config: config, opts: opts}, startupContext(config))
+	}
+
+	// client-go logs watch failures through klog, which would print over the TUI
+	klog.LogToStderr(false)
+	klog.SetOutput(io.Discard)
 
 	p := tea.NewProgram(model)
 	if _, err := p.Run(); err != nil {
@@ -39,3 +107,167 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// newMultiClusterService inspects every selected context, one context is inspected without
+// a cluster column. Of several contexts, the ones that can not be opened are reported as
+// failed clusters.
+func newMultiClusterService(config *configs.Config, opts []service.Option) (service.SecretsService, error) {
+	contexts := config.Contexts()
+	if config.AllContexts {
+		var err error
+		if contexts, err = client.KubeconfigContexts(config.KubeConfigPath); err != nil {
+			return nil, fmt.Errorf("can not read the kubeconfig contexts: %w", err)
+		}
+	}
+
+	if config.LocalFiles() || len(contexts) == 0 {
+		return newService(config, "", opts)
+	}
+	if len(contexts) == 1 {
+		return newService(config, contexts[0], opts)
+	}
+
+	clusters := make([]service.Cluster, 0, len(contexts))
+	for _, context := range contexts {
+		svc, err := newService(config, context, opts)
+		clusters = append(clusters, service.Cluster{Name: context, Service: svc, Err: err})
+	}
+	return service.NewMultiClusterService(clusters...), nil
+}
+
+// startupContext is the single context inspected at startup, empty for the kubeconfig's
+// current context or several contexts.
+func startupContext(config *configs.Config) string {
+	if contexts := config.Contexts(); len(contexts) == 1 && !config.AllContexts {
+		return contexts[0]
+	}
+	return ""
+}
+
+func newService(config *configs.Config, context string, opts []service.Option) (service.SecretsService, error) {
+	opts = slices.Clone(opts) // shared by the services of all clusters
+	repo, err := newRepository(config, context)
+	if err != nil {
+		return nil, err
+	}
+
+	if !config.LocalFiles() {
+		resourceFetcher, err := client.NewResourceFetcher(config.KubeConfigPath, context, time.Duration(config.RequestTimeout))
+		if err != nil {
+			return nil, err
+		}
+		repo = repository.NewCABundleRepository(repo, resourceFetcher)
+		opts = append(opts,
+			service.WithCertManager(repository.NewCertManagerRepository(resourceFetcher)),
+			service.WithReferences(repository.NewReferencesRepository(resourceFetcher)),
+		)
+	}
+
+	if config.Target != "" {
+		prober, err := newProber(config, context)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, service.WithProber(repository.NewProbeRepository(prober)))
+	}
+
+	return service.NewSecretsService(repo, opts...), nil
+}
+
+func newRepository(config *configs.Config, context string) (repository.SecretsRepository, error) {
+	if config.LocalFiles() {
+		var files, dirs []string
+		if config.File != "" {
+			files = append(files, config.File)
+		}
+		if config.Dir != "" {
+			dirs = append(dirs, config.Dir)
+		}
+		return repository.NewFileRepository(files, dirs), nil
+	}
+
+	kubeClient, err := client.NewSecretsFetcher(config.KubeConfigPath, context, time.Duration(config.RequestTimeout))
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Scan {
+		configMapsClient, err := client.NewConfigMapsFetcher(config.KubeConfigPath, context, time.Duration(config.RequestTimeout))
+		if err != nil {
+			return nil, err
+		}
+		return repository.NewScanRepository(kubeClient, configMapsClient), nil
+	}
+
+	return repository.NewSecretsRepository(kubeClient), nil
+}
+
+// newProber dials endpoints directly for local files, Services can only be probed with a cluster.
+func newProber(config *configs.Config, context string) (client.TLSProber, error) {
+	if config.LocalFiles() {
+		return client.NewLocalTLSProber(), nil
+	}
+	return client.NewTLSProber(config.KubeConfigPath, context, time.Duration(config.RequestTimeout))
+}
+
+// expiryPolicy reads -warn and -critical and the per namespace thresholds of -thresholds.
+func expiryPolicy(config *configs.Config) (service.ExpiryPolicy, error) {
+	warning, err := configs.ParseThreshold(config.Warn)
+	if err != nil {
+		return service.ExpiryPolicy{}, fmt.Errorf("invalid -warn: %w", err)
+	}
+	critical, err := configs.ParseThreshold(config.Critical)
+	if err != nil {
+		return service.ExpiryPolicy{}, fmt.Errorf("invalid -critical: %w", err)
+	}
+
+	defaults := service.ExpiryThresholds{Warning: warning, Critical: critical}
+	if err := defaults.Validate(); err != nil {
+		return service.ExpiryPolicy{}, fmt.Errorf("invalid -warn and -critical: %w", err)
+	}
+	if config.Thresholds == "" {
+		return service.ExpiryPolicy{ExpiryThresholds: defaults}, nil
+	}
+	return configs.LoadExpiryPolicy(config.Thresholds, defaults)
+}
+
+func runCheck(ctx context.Context, svc service.SecretsService, config *configs.Config) int {
+	opts := cli.CheckOptions{
+		Namespace: config.Namespace,
+		Name:      config.Name,
+	}
+	if config.FailOn != "" {
+		severity, err := service.ParseSeverity(config.FailOn)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return cli.ExitError
+		}
+		opts.FailOn = &severity
+	}
+
+	return cli.RunCheck(ctx, svc, opts, os.Stdout, os.Stderr)
+}
+
+func runExport(ctx context.Context, svc service.SecretsService, config *configs.Config) int {
+	format, err := export.ParseFormat(config.Format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return cli.ExitError
+	}
+
+	out := os.Stdout
+	if config.Output != "" {
+		out, err = os.Create(config.Output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: can not create %s: %v\n", config.Output, err)
+			return cli.ExitError
+		}
+		defer out.Close()
+	}
+
+	return cli.RunExport(ctx, svc, cli.ExportOptions{
+		Namespace: config.Namespace,
+		Name:      config.Name,
+		Format:    format,
+	}, out, os.Stderr)
+}
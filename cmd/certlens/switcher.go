@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/codechamp1/certlens/configs"
+	"github.com/codechamp1/certlens/internal/client"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+// switcher opens the namespaces and contexts picked in the TUI.
+type switcher struct {
+	config *configs.Config
+	opts   []service.Option
+}
+
+func (s switcher) Contexts() ([]string, error) {
+	return client.KubeconfigContexts(s.config.KubeConfigPath)
+}
+
+func (s switcher) Namespaces(ctx context.Context, kubeContext string) ([]string, error) {
+	fetcher, err := client.NewNamespacesFetcher(s.config.KubeConfigPath, kubeContext, time.Duration(s.config.RequestTimeout))
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces, err := fetcher.FetchNamespaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(namespaces.Items))
+	for _, namespace := range namespaces.Items {
+		names = append(names, namespace.Name)
+	}
+	return names, nil
+}
+
+func (s switcher) Service(context string) (service.SecretsService, error) {
+	svc, err := newService(s.config, context, s.opts)
+	if err != nil {
+		return nil, fmt.Errorf("can not connect to %s: %w", context, err)
+	}
+	return svc, nil
+}
@@ -2,24 +2,148 @@ package configs
 
 import (
 	"flag"
+	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"k8s.io/client-go/util/homedir"
 )
 
+const (
+	CommandTUI          = ""
+	CommandCheck        = "check"
+	CommandExport       = "export"
+	CommandServeMetrics = "serve-metrics"
+	CommandProbe        = "probe"
+)
+
+var commands = []string{CommandCheck, CommandExport, CommandServeMetrics, CommandProbe}
+
 type Config struct {
+	Command        string `json:"command,omitempty"`
 	Context        string `json:"context,omitempty"`
+	AllContexts    bool   `json:"allContexts,omitempty"`
 	KubeConfigPath string `json:"kubeConfigPath,omitempty"`
 	Namespace      string `json:"namespace,omitempty"`
 	Name           string `json:"name,omitempty"`
+	TrustBundle    string `json:"trustBundle,omitempty"`
+	File           string `json:"file,omitempty"`
+	Dir            string `json:"dir,omitempty"`
+	Watch          bool   `json:"watch,omitempty"`
+	Scan           bool   `json:"scan,omitempty"`
+	// Target is the live TLS endpoint compared with the secret, host:port or service/name:port
+	Target string `json:"target,omitempty"`
+	// RequestTimeout bounds every single API server request, 0 waits forever
+	RequestTimeout Duration `json:"requestTimeout,omitempty"`
+	// Policy is a YAML file of organisation rules checked alongside the built-in lint rules
+	Policy string `json:"policy,omitempty"`
+
+	// Warn and Critical are the expiry thresholds, a duration (30d) or a percentage of the validity (25%)
+	Warn     string `json:"warn,omitempty"`
+	Critical string `json:"critical,omitempty"`
+	// Thresholds is a YAML file of expiry thresholds overriding Warn and Critical per namespace
+	Thresholds string `json:"thresholds,omitempty"`
+
+	// tui
+	ExportDir string `json:"exportDir,omitempty"`
+
+	// check
+	FailOn string `json:"failOn,omitempty"`
+
+	// export
+	Format string `json:"format,omitempty"`
+	Output string `json:"output,omitempty"`
+
+	// serve-metrics
+	ListenAddress   string   `json:"listenAddress,omitempty"`
+	RefreshInterval Duration `json:"refreshInterval,omitempty"`
 }
 
 func Load() *Config {
 	config := &Config{}
-	flag.StringVar(&config.Context, "context", "", "context to use from kubeconfig, if not set, the current context will be used")
-	flag.StringVar(&config.KubeConfigPath, "kubeconfig", filepath.Join(homedir.HomeDir(), ".kube", "config"), "path to a kubeconfig")
-	flag.StringVar(&config.Namespace, "namespace", "", "namespace to lens, if not set, all namespaces will be used")
-	flag.StringVar(&config.Name, "name", "", "name of the secret to lens, if not set, all secrets will be listed")
-	flag.Parse()
+	args := os.Args[1:]
+
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		config.Command, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet(strings.TrimSpace("certlens "+config.Command), flag.ExitOnError)
+	fs.StringVar(&config.Context, "context", "", "context to use from kubeconfig, a comma separated list inspects several clusters at once, if not set, the current context will be used")
+	fs.BoolVar(&config.AllContexts, "all-contexts", false, "inspect the clusters of all contexts in the kubeconfig at once")
+	fs.StringVar(&config.KubeConfigPath, "kubeconfig", filepath.Join(homedir.HomeDir(), ".kube", "config"), "path to a kubeconfig")
+	fs.StringVar(&config.Namespace, "namespace", "", "namespace to lens, if not set, all namespaces will be used")
+	fs.StringVar(&config.Name, "name", "", "name of the secret to lens, if not set, all secrets will be listed")
+	fs.StringVar(&config.File, "file", "", "inspect a local PEM or DER certificate file instead of a cluster")
+	fs.StringVar(&config.Dir, "dir", "", "inspect the certificate files (.pem, .crt, .cer, .der, .jks) of a local directory instead of a cluster")
+	fs.BoolVar(&config.Scan, "scan", false, "also list the Opaque secrets and ConfigMaps holding certificates, such as CA bundles and truststores")
+	fs.StringVar(&config.TrustBundle, "trust-bundle", "", "path to a PEM bundle of root certificates used for chain validation, if not set, the system roots will be used")
+	fs.StringVar(&config.Policy, "policy", "", "path to a YAML file of certificate policy rules, violations are reported as lint findings")
+	config.RequestTimeout = Duration(30 * time.Second)
+	fs.Var(&config.RequestTimeout, "request-timeout", "how long to wait for a single API server request before giving up, 0 waits forever (e.g. 30s, 1m)")
+
+	// check keeps its absolute defaults, the other commands judge by the share of validity left
+	warn, critical := "25%", "10%"
+	if config.Command == CommandCheck {
+		warn, critical = "30d", "7d"
+	}
+	fs.StringVar(&config.Warn, "warn", warn, "report certificates as warning within this remaining validity, a duration or a percentage of the validity (e.g. 30d, 12h, 25%)")
+	fs.StringVar(&config.Critical, "critical", critical, "report certificates as critical within this remaining validity, a duration or a percentage of the validity (e.g. 7d, 12h, 10%)")
+	fs.StringVar(&config.Thresholds, "thresholds", "", "path to a YAML file of expiry thresholds per namespace, overriding -warn and -critical")
+
+	switch config.Command {
+	case CommandTUI:
+		fs.BoolVar(&config.Watch, "watch", true, "watch TLS secrets and update the list live")
+		fs.StringVar(&config.Target, "probe", "", "compare the secret lensed with -name with the chain served by a live endpoint, host:port or service/name:port")
+		fs.StringVar(&config.Format, "format", "json", "format preselected when exporting the inventory with e: json, yaml or csv")
+		fs.StringVar(&config.ExportDir, "export-dir", ".", "directory the inventory exported with e is written to")
+	case CommandCheck:
+		fs.StringVar(&config.FailOn, "fail-on", "", "fail on lint findings of at least this severity: info, warning or error, if not set, findings are only reported")
+	case CommandExport:
+		fs.StringVar(&config.Format, "format", "json", "export format: json, yaml or csv")
+		fs.StringVar(&config.Output, "output", "", "file to write the export to, if not set, stdout will be used")
+	case CommandServeMetrics:
+		config.RefreshInterval = Duration(time.Minute)
+		fs.StringVar(&config.ListenAddress, "listen-address", ":8080", "address to expose /metrics on")
+		fs.Var(&config.RefreshInterval, "interval", "how often the TLS secrets are inspected (e.g. 1m, 1d)")
+	case CommandProbe:
+		fs.StringVar(&config.Target, "target", "", "live TLS endpoint to probe, host:port or service/name:port (port-forwarded in -namespace)")
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q, available commands: %s\n", config.Command, strings.Join(commands, ", "))
+		os.Exit(2)
+	}
+
+	_ = fs.Parse(args) // ExitOnError
+	if err := config.Validate(); err != nil {
+		// reported like an invalid flag value
+		fmt.Fprintln(fs.Output(), err)
+		fs.Usage()
+		os.Exit(2)
+	}
 	return config
 }
+
+// Validate rejects flag values that parse but can not be used.
+func (c *Config) Validate() error {
+	if c.Command == CommandServeMetrics && c.RefreshInterval <= 0 {
+		return fmt.Errorf("invalid value %q for flag -interval: must be positive", c.RefreshInterval.String())
+	}
+	return nil
+}
+
+// Contexts returns the contexts selected with -context, an empty list selects the current context.
+func (c *Config) Contexts() []string {
+	var contexts []string
+	for _, context := range strings.Split(c.Context, ",") {
+		if context = strings.TrimSpace(context); context != "" {
+			contexts = append(contexts, context)
+		}
+	}
+	return contexts
+}
+
+// LocalFiles reports whether certificates are read from the filesystem instead of a cluster.
+func (c *Config) LocalFiles() bool {
+	return c.File != "" || c.Dir != ""
+}
@@ -0,0 +1,29 @@
+package configs_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codechamp1/certlens/configs"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    configs.Config
+		expectErr bool
+	}{
+		{name: "Should accept a positive refresh interval", config: configs.Config{Command: configs.CommandServeMetrics, RefreshInterval: configs.Duration(time.Minute)}},
+		{name: "Should reject a zero refresh interval", config: configs.Config{Command: configs.CommandServeMetrics}, expectErr: true},
+		{name: "Should reject a negative refresh interval", config: configs.Config{Command: configs.CommandServeMetrics, RefreshInterval: configs.Duration(-time.Minute)}, expectErr: true},
+		{name: "Should ignore the refresh interval of other commands", config: configs.Config{Command: configs.CommandCheck}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); (err != nil) != tt.expectErr {
+				t.Errorf("expected error: %v, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package configs
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+// policyFile is the YAML file of -policy, see service.PolicyRule.
+type policyFile struct {
+	Rules []policyRule `json:"rules"`
+}
+
+type policyRule struct {
+	ID          string            `json:"id"`
+	Description string            `json:"description"`
+	Severity    *service.Severity `json:"severity"`
+	Match       policySelector    `json:"match"`
+	Require     []policyPredicate `json:"require"`
+}
+
+type policySelector struct {
+	Namespaces   []string          `json:"namespaces"`
+	Names        []string          `json:"names"`
+	Labels       map[string]string `json:"labels"`
+	Certificates string            `json:"certificates"`
+}
+
+type policyPredicate struct {
+	Field   string   `json:"field"`
+	Equals  *string  `json:"equals"`
+	OneOf   []string `json:"oneOf"`
+	Matches string   `json:"matches"`
+	Min     string   `json:"min"`
+	Max     string   `json:"max"`
+}
+
+// LoadPolicy reads a YAML policy file and compiles its rules, they are checked alongside the
+// service.DefaultLintRules and reported as findings.
+func LoadPolicy(path string) ([]service.LintRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("can not read policy %s: %w", path, err)
+	}
+
+	rules, err := ParsePolicy(data)
+	if err != nil {
+		return nil, fmt.Errorf("can not parse policy %s: %w", path, err)
+	}
+
+	return rules, nil
+}
+
+// ParsePolicy compiles the rules of a YAML policy, rules without a severity report errors.
+func ParsePolicy(data []byte) ([]service.LintRule, error) {
+	var file policyFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, err
+	}
+
+	rules := make([]service.PolicyRule, 0, len(file.Rules))
+	for _, rule := range file.Rules {
+		severity := service.SeverityError
+		if rule.Severity != nil {
+			severity = *rule.Severity
+		}
+
+		require := make([]service.PolicyPredicate, 0, len(rule.Require))
+		for _, predicate := range rule.Require {
+			require = append(require, service.PolicyPredicate{
+				Field:   predicate.Field,
+				Equals:  predicate.Equals,
+				OneOf:   predicate.OneOf,
+				Matches: predicate.Matches,
+				Min:     parseBound(predicate.Min),
+				Max:     parseBound(predicate.Max),
+			})
+		}
+
+		rules = append(rules, service.PolicyRule{
+			ID:          rule.ID,
+			Description: rule.Description,
+			Severity:    severity,
+			Match:       service.PolicySelector(rule.Match),
+			Require:     require,
+		})
+	}
+
+	return service.CompilePolicy(rules)
+}
+
+// parseBound reads a min or max as a number and as a duration (e.g. 90d, 2160h), the field it
+// bounds decides which one applies. An empty bound is nil.
+func parseBound(value string) *service.PolicyBound {
+	if value == "" {
+		return nil
+	}
+
+	bound := &service.PolicyBound{Text: value}
+	if n, err := strconv.ParseFloat(value, 64); err == nil {
+		bound.Number = &n
+	}
+	if d, err := ParseDuration(value); err == nil {
+		bound.Duration = &d
+	}
+	return bound
+}
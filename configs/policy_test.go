@@ -0,0 +1,56 @@
+package configs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/codechamp1/certlens/configs"
+)
+
+func TestParsePolicyErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   string
+		expected string
+	}{
+		{
+			name:     "Should require an id",
+			policy:   "rules:\n- require:\n  - field: issuer\n    equals: x\n",
+			expected: "id is required",
+		},
+		{
+			name:     "Should reject unknown fields",
+			policy:   "rules:\n- id: r\n  require:\n  - field: owner\n    equals: x\n",
+			expected: "unknown certificate field",
+		},
+		{
+			name:     "Should reject bounds on text fields",
+			policy:   "rules:\n- id: r\n  require:\n  - field: issuer\n    max: \"3\"\n",
+			expected: "min and max only apply to numbers and durations",
+		},
+		{
+			name:     "Should reject invalid patterns",
+			policy:   "rules:\n- id: r\n  require:\n  - field: issuer\n    matches: \"(\"\n",
+			expected: "invalid matches pattern",
+		},
+		{
+			name:     "Should reject unknown severities",
+			policy:   "rules:\n- id: r\n  severity: fatal\n  require:\n  - field: issuer\n    equals: x\n",
+			expected: "unknown severity",
+		},
+		{
+			name:     "Should reject unknown keys",
+			policy:   "rules:\n- id: r\n  requires: []\n",
+			expected: "unknown field",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := configs.ParsePolicy([]byte(tt.policy))
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
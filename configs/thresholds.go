@@ -0,0 +1,106 @@
+package configs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+// ParseThreshold parses a percentage such as 25% or a duration such as 30d or 12h.
+func ParseThreshold(value string) (service.Threshold, error) {
+	if percent, ok := strings.CutSuffix(value, "%"); ok {
+		n, err := strconv.ParseFloat(percent, 64)
+		if err != nil || n < 0 || n > 100 {
+			return service.Threshold{}, fmt.Errorf("invalid percentage %q, expected 0%% to 100%%", value)
+		}
+		return service.Threshold{Percent: n}, nil
+	}
+
+	d, err := ParseDuration(value)
+	if err != nil {
+		return service.Threshold{}, err
+	}
+	if d < 0 {
+		return service.Threshold{}, fmt.Errorf("invalid duration %q, thresholds can not be negative", value)
+	}
+	return service.Threshold{Duration: d}, nil
+}
+
+// threshold reads a service.Threshold from YAML.
+type threshold struct {
+	service.Threshold
+}
+
+// UnmarshalJSON also accepts numbers, YAML decodes an unquoted 0 as one.
+func (t *threshold) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		text = string(data)
+	}
+
+	parsed, err := ParseThreshold(text)
+	if err != nil {
+		return err
+	}
+	t.Threshold = parsed
+	return nil
+}
+
+// thresholdsFile is the YAML file of -thresholds, omitted thresholds are nil.
+type thresholdsFile struct {
+	Warning    *threshold `json:"warning"`
+	Critical   *threshold `json:"critical"`
+	Namespaces []struct {
+		Match    string     `json:"match"`
+		Warning  *threshold `json:"warning"`
+		Critical *threshold `json:"critical"`
+	} `json:"namespaces"`
+}
+
+// LoadExpiryPolicy reads a YAML file of expiry thresholds, thresholds it omits are taken
+// from defaults. The policy must pass service.ExpiryPolicy.Validate.
+func LoadExpiryPolicy(file string, defaults service.ExpiryThresholds) (service.ExpiryPolicy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return service.ExpiryPolicy{}, fmt.Errorf("can not read thresholds %s: %w", file, err)
+	}
+
+	var parsed thresholdsFile
+	if err := yaml.UnmarshalStrict(data, &parsed); err != nil {
+		return service.ExpiryPolicy{}, fmt.Errorf("can not parse thresholds %s: %w", file, err)
+	}
+
+	policy := service.ExpiryPolicy{ExpiryThresholds: defaults}
+	if parsed.Warning != nil {
+		policy.Warning = parsed.Warning.Threshold
+	}
+	if parsed.Critical != nil {
+		policy.Critical = parsed.Critical.Threshold
+	}
+	for _, namespace := range parsed.Namespaces {
+		policy.Namespaces = append(policy.Namespaces, service.NamespaceThresholds{
+			Match:    namespace.Match,
+			Warning:  namespace.Warning.value(),
+			Critical: namespace.Critical.value(),
+		})
+	}
+
+	if err := policy.Validate(); err != nil {
+		return service.ExpiryPolicy{}, fmt.Errorf("can not parse thresholds %s: %w", file, err)
+	}
+	return policy, nil
+}
+
+// value returns the threshold read, nil if it was omitted.
+func (t *threshold) value() *service.Threshold {
+	if t == nil {
+		return nil
+	}
+	return &t.Threshold
+}
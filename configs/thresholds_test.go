@@ -0,0 +1,103 @@
+package configs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/codechamp1/certlens/configs"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+func TestParseThreshold(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  service.Threshold
+		expectErr bool
+	}{
+		{value: "25%", expected: service.Threshold{Percent: 25}},
+		{value: "30d", expected: service.Threshold{Duration: 30 * 24 * time.Hour}},
+		{value: "12h", expected: service.Threshold{Duration: 12 * time.Hour}},
+		{value: "0", expected: service.Threshold{}},
+		{value: "120%", expectErr: true},
+		{value: "-7d", expectErr: true},
+		{value: "soon", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			threshold, err := configs.ParseThreshold(tt.value)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("expected an error, got %v", threshold)
+				}
+				return
+			}
+			if err != nil || threshold != tt.expected {
+				t.Errorf("expected %v, got %v (%v)", tt.expected, threshold, err)
+			}
+		})
+	}
+}
+
+func TestLoadExpiryPolicy(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "thresholds.yaml")
+	data := `
+warning: 30d
+namespaces:
+- match: "prod-*"
+  warning: 25%
+  critical: 10%
+- match: "dev-*"
+  warning: 0
+  critical: 0%
+- match: "*"
+  critical: 1d
+`
+	if err := os.WriteFile(file, []byte(data), 0o600); err != nil {
+		t.Fatalf("failed to write thresholds: %v", err)
+	}
+
+	policy, err := configs.LoadExpiryPolicy(file, service.ExpiryThresholds{
+		Warning:  service.Threshold{Duration: time.Hour},
+		Critical: service.Threshold{Duration: 7 * 24 * time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		namespace string
+		expected  string
+	}{
+		{namespace: "prod-eu", expected: "25%/10%"},
+		{namespace: "dev-eu", expected: "0s/0s"},
+		{namespace: "default", expected: "720h0m0s/24h0m0s"},
+	}
+	for _, tt := range tests {
+		thresholds := policy.For(tt.namespace)
+		if got := thresholds.Warning.String() + "/" + thresholds.Critical.String(); got != tt.expected {
+			t.Errorf("expected thresholds %s in %s, got %s", tt.expected, tt.namespace, got)
+		}
+	}
+
+	if err := os.WriteFile(file, []byte("warning: soon\n"), 0o600); err != nil {
+		t.Fatalf("failed to write thresholds: %v", err)
+	}
+	if _, err := configs.LoadExpiryPolicy(file, service.DefaultExpiryThresholds); err == nil {
+		t.Error("expected an error for an invalid threshold")
+	}
+
+	data = `
+namespaces:
+- match: "prod-*"
+  critical: 30%
+`
+	if err := os.WriteFile(file, []byte(data), 0o600); err != nil {
+		t.Fatalf("failed to write thresholds: %v", err)
+	}
+	if _, err := configs.LoadExpiryPolicy(file, service.DefaultExpiryThresholds); err == nil {
+		t.Error("expected an error for a namespace critical threshold above its warning threshold")
+	}
+}
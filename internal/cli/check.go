@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+const (
+	ExitOK       = 0
+	ExitError    = 1
+	ExitCritical = 2
+)
+
+type CheckOptions struct {
+	Namespace string
+	Name      string
+	// FailOn fails the check on lint findings of at least this severity, nil only reports them.
+	FailOn *service.Severity
+}
+
+type checkStatus int
+
+const (
+	checkOK checkStatus = iota
+	checkWarning
+	checkCritical
+	checkExpired
+	checkInvalid
+)
+
+var checkStatusStrings = map[checkStatus]string{
+	checkOK:       "OK",
+	checkWarning:  "Warning",
+	checkCritical: "Critical",
+	checkExpired:  "Expired",
+	checkInvalid:  "Invalid",
+}
+
+func (c checkStatus) String() string {
+	if str, ok := checkStatusStrings[c]; ok {
+		return str
+	}
+	return "Unknown"
+}
+
+// checkStatuses maps the ExpiryStatus the service derived from the expiry thresholds.
+var checkStatuses = map[string]checkStatus{
+	service.StatusOK.String():       checkOK,
+	service.StatusWarning.String():  checkWarning,
+	service.StatusCritical.String(): checkCritical,
+	service.StatusExpired.String():  checkExpired,
+}
+
+func (c checkStatus) failing() bool {
+	return c >= checkCritical
+}
+
+// RunCheck inspects the TLS secrets selected by opts, writes a summary table to w and errors
+// to errW, and returns the process exit code: ExitCritical when any certificate is critical,
+// expired or unparsable, or when a referencing Ingress or Gateway serves a host the certificate
+// does not cover. Lint findings fail the check if they reach opts.FailOn. Clusters that can not
+// be inspected are reported and return ExitError unless another cluster fails the check.
+func RunCheck(ctx context.Context, svc service.SecretsService, opts CheckOptions, w io.Writer, errW io.Writer) int {
+	inspections, partial, err := inspect(ctx, svc, opts.Namespace, opts.Name)
+	if err != nil {
+		_, _ = fmt.Fprintf(errW, "Error: %v\n", err)
+		return ExitError
+	}
+	reportPartial(partial, errW)
+
+	counts := map[checkStatus]int{}
+	var uncovered, findings []string
+	failingFindings := 0
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "NAMESPACE\tNAME\t#\tSUBJECT\tNOT AFTER\tREMAINING\tSTATUS")
+
+	for _, inspection := range inspections {
+		if inspection.Err != nil {
+			counts[checkInvalid]++
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t-\t-\t-\t-\t%s (%v)\n", inspection.QualifiedNamespace(), inspection.Ref(), checkInvalid, inspection.Err)
+			continue
+		}
+
+		for _, host := range inspection.UncoveredHosts {
+			uncovered = append(uncovered, fmt.Sprintf("%s/%s: %s", inspection.QualifiedNamespace(), inspection.Name, host))
+		}
+
+		for _, finding := range inspection.Findings {
+			findings = append(findings, fmt.Sprintf("%s/%s #%d [%s] %s: %s",
+				inspection.QualifiedNamespace(), inspection.Ref(), finding.Position+1, finding.Severity, finding.ID, finding.Message))
+			if opts.FailOn != nil && finding.Severity >= *opts.FailOn {
+				failingFindings++
+			}
+		}
+
+		for i, cert := range inspection.Certificates {
+			status := certStatus(cert)
+			counts[status]++
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+				inspection.QualifiedNamespace(), inspection.Ref(), i+1, cert.Subject, cert.NotAfter, formatRemaining(time.Duration(cert.TimeUntilExpiry)), status)
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return ExitError
+	}
+
+	if len(uncovered) > 0 {
+		_, _ = fmt.Fprintln(w, "\nHosts not covered by the certificate SANs:")
+		for _, host := range uncovered {
+			_, _ = fmt.Fprintln(w, "  "+host)
+		}
+	}
+
+	if len(findings) > 0 {
+		_, _ = fmt.Fprintln(w, "\nLint findings:")
+		for _, finding := range findings {
+			_, _ = fmt.Fprintln(w, "  "+finding)
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "\n%d secrets checked, certificates: %d OK, %d warning, %d critical, %d expired, %d invalid, %d uncovered hosts, %d lint findings\n",
+		len(inspections), counts[checkOK], counts[checkWarning], counts[checkCritical], counts[checkExpired], counts[checkInvalid], len(uncovered), len(findings))
+
+	if len(uncovered) > 0 || failingFindings > 0 {
+		return ExitCritical
+	}
+
+	for status, count := range counts {
+		if status.failing() && count > 0 {
+			return ExitCritical
+		}
+	}
+
+	if partial != nil {
+		return ExitError
+	}
+	return ExitOK
+}
+
+// inspect returns the inspections selected by namespace and name. The clusters or sources that
+// failed are returned as partial alongside the inspections of the others.
+func inspect(ctx context.Context, svc service.SecretsService, namespace, name string) ([]service.TLSSecretInspection, *domains.PartialError, error) {
+	if name == "" {
+		inspections, err := svc.InspectTLSSecrets(ctx, namespace)
+		var partial *domains.PartialError
+		if errors.As(err, &partial) {
+			return inspections, partial, nil
+		}
+		return inspections, nil, err
+	}
+
+	inspection, err := svc.InspectTLSSecret(ctx, namespace, name)
+	if errors.Is(err, service.ErrUnparsable) {
+		// reported like the unparsable secrets of a listing
+		inspection = service.TLSSecretInspection{K8SResourceID: domains.K8SResourceID{Name: name, Namespace: namespace}, Err: err}
+	} else if err != nil {
+		return nil, nil, err
+	}
+	return []service.TLSSecretInspection{inspection}, nil, nil
+}
+
+func reportPartial(partial *domains.PartialError, errW io.Writer) {
+	if partial == nil {
+		return
+	}
+	for _, err := range partial.Errs {
+		_, _ = fmt.Fprintf(errW, "Error: %v\n", err)
+	}
+}
+
+func certStatus(cert service.CertificateInfo) checkStatus {
+	if cert.Expired {
+		return checkExpired
+	}
+	if status, ok := checkStatuses[cert.ExpiryStatus]; ok {
+		return status
+	}
+	return checkInvalid
+}
+
+func formatRemaining(d time.Duration) string {
+	if d <= 0 {
+		return "0d"
+	}
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	return fmt.Sprintf("%dd%dh", days, hours)
+}
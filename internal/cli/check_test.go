@@ -0,0 +1,230 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codechamp1/certlens/internal/cli"
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+var errTest = errors.New("simulated error")
+
+// certExpiringIn returns a certificate with the expiry status the service derived.
+func certExpiringIn(d time.Duration, status service.Status) service.CertificateInfo {
+	return service.CertificateInfo{
+		CertificateRawInfo: service.CertificateRawInfo{Subject: "CN=localhost"},
+		CertificateComputedInfo: service.CertificateComputedInfo{
+			Expired:         status == service.StatusExpired,
+			TimeUntilExpiry: service.Duration(d),
+			ExpiryStatus:    status.String(),
+		},
+	}
+}
+
+func TestRunCheck(t *testing.T) {
+	day := 24 * time.Hour
+	failOnError := service.SeverityError
+
+	tests := []struct {
+		name             string
+		inspections      []service.TLSSecretInspection
+		svcErr           error
+		failOn           *service.Severity
+		expectedExitCode int
+		expectedOutput   []string
+		expectedErrors   []string
+	}{
+		{
+			name:             "Should fail with an error exit code if secrets can not be listed",
+			svcErr:           errTest,
+			expectedExitCode: cli.ExitError,
+			expectedErrors:   []string{"Error: simulated error"},
+		},
+		{
+			name: "Should pass when certificates are only in the warning window",
+			inspections: []service.TLSSecretInspection{
+				{
+					K8SResourceID: domains.K8SResourceID{Name: "ok", Namespace: "default"},
+					Certificates:  []service.CertificateInfo{certExpiringIn(90*day, service.StatusOK)},
+				},
+				{
+					K8SResourceID: domains.K8SResourceID{Name: "soon", Namespace: "default"},
+					Certificates:  []service.CertificateInfo{certExpiringIn(20*day, service.StatusWarning)},
+				},
+			},
+			expectedExitCode: cli.ExitOK,
+			expectedOutput:   []string{"1 OK, 1 warning, 0 critical, 0 expired, 0 invalid"},
+		},
+		{
+			name: "Should fail when a certificate of the chain is critical",
+			inspections: []service.TLSSecretInspection{
+				{
+					K8SResourceID: domains.K8SResourceID{Name: "chain", Namespace: "default"},
+					Certificates:  []service.CertificateInfo{certExpiringIn(90*day, service.StatusOK), certExpiringIn(3*day, service.StatusCritical)},
+				},
+			},
+			expectedExitCode: cli.ExitCritical,
+			expectedOutput:   []string{"1 OK, 0 warning, 1 critical"},
+		},
+		{
+			name: "Should fail when a certificate is expired",
+			inspections: []service.TLSSecretInspection{
+				{
+					K8SResourceID: domains.K8SResourceID{Name: "old", Namespace: "default"},
+					Certificates:  []service.CertificateInfo{certExpiringIn(-day, service.StatusExpired)},
+				},
+			},
+			expectedExitCode: cli.ExitCritical,
+			expectedOutput:   []string{"1 expired"},
+		},
+		{
+			name: "Should fail when a referencing resource serves a host the certificate does not cover",
+			inspections: []service.TLSSecretInspection{
+				{
+					K8SResourceID:  domains.K8SResourceID{Name: "web", Namespace: "default"},
+					Certificates:   []service.CertificateInfo{certExpiringIn(90*day, service.StatusOK)},
+					UncoveredHosts: []string{"example.org (Ingress default/web)"},
+				},
+			},
+			expectedExitCode: cli.ExitCritical,
+			expectedOutput:   []string{"default/web: example.org (Ingress default/web)", "1 uncovered hosts"},
+		},
+		{
+			name: "Should fail when a secret can not be parsed",
+			inspections: []service.TLSSecretInspection{
+				{
+					K8SResourceID: domains.K8SResourceID{Name: "broken", Namespace: "default"},
+					Err:           errTest,
+				},
+			},
+			expectedExitCode: cli.ExitCritical,
+			expectedOutput:   []string{"broken", "Invalid (simulated error)", "1 invalid"},
+		},
+		{
+			name: "Should check the reachable clusters and fail with an error exit code for the others",
+			inspections: []service.TLSSecretInspection{
+				{
+					K8SResourceID: domains.K8SResourceID{Name: "ok", Namespace: "default", Cluster: "prod"},
+					Certificates:  []service.CertificateInfo{certExpiringIn(90*day, service.StatusOK)},
+				},
+			},
+			svcErr:           &domains.PartialError{Errs: []error{fmt.Errorf("cluster staging: %w", errTest)}},
+			expectedExitCode: cli.ExitError,
+			expectedOutput:   []string{"prod/default", "1 OK"},
+			expectedErrors:   []string{"Error: cluster staging: simulated error"},
+		},
+		{
+			name: "Should only report lint findings by default",
+			inspections: []service.TLSSecretInspection{
+				{
+					K8SResourceID: domains.K8SResourceID{Name: "web", Namespace: "default"},
+					Certificates:  []service.CertificateInfo{certExpiringIn(90*day, service.StatusOK)},
+					Findings:      []service.Finding{{ID: "sha1-signature", Severity: service.SeverityError, Message: "signed with SHA1-RSA"}},
+				},
+			},
+			expectedExitCode: cli.ExitOK,
+			expectedOutput:   []string{"default/web #1 [error] sha1-signature: signed with SHA1-RSA", "1 lint findings"},
+		},
+		{
+			name: "Should fail on lint findings reaching the fail-on severity",
+			inspections: []service.TLSSecretInspection{
+				{
+					K8SResourceID: domains.K8SResourceID{Name: "web", Namespace: "default"},
+					Certificates:  []service.CertificateInfo{certExpiringIn(90*day, service.StatusOK)},
+					Findings:      []service.Finding{{ID: "sha1-signature", Severity: service.SeverityError, Message: "signed with SHA1-RSA"}},
+				},
+			},
+			failOn:           &failOnError,
+			expectedExitCode: cli.ExitCritical,
+			expectedOutput:   []string{"1 lint findings"},
+		},
+		{
+			name: "Should pass on lint findings below the fail-on severity",
+			inspections: []service.TLSSecretInspection{
+				{
+					K8SResourceID: domains.K8SResourceID{Name: "web", Namespace: "default"},
+					Certificates:  []service.CertificateInfo{certExpiringIn(90*day, service.StatusOK)},
+					Findings:      []service.Finding{{ID: "duplicate-san", Severity: service.SeverityWarning, Message: "duplicate subject alternative names: a"}},
+				},
+			},
+			failOn:           &failOnError,
+			expectedExitCode: cli.ExitOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewMockSecretService(nil, nil, nil, func(ctx context.Context, namespace string) ([]service.TLSSecretInspection, error) {
+				return tt.inspections, tt.svcErr
+			}, nil, nil, nil)
+
+			var out, errOut bytes.Buffer
+			exitCode := cli.RunCheck(context.Background(), svc, cli.CheckOptions{FailOn: tt.failOn}, &out, &errOut)
+
+			if exitCode != tt.expectedExitCode {
+				t.Errorf("expected exit code %d, got %d", tt.expectedExitCode, exitCode)
+			}
+
+			for _, expected := range tt.expectedOutput {
+				if !strings.Contains(out.String(), expected) {
+					t.Errorf("expected output to contain %q, got:\n%s", expected, out.String())
+				}
+			}
+
+			for _, expected := range tt.expectedErrors {
+				if !strings.Contains(errOut.String(), expected) {
+					t.Errorf("expected errors to contain %q, got:\n%s", expected, errOut.String())
+				}
+			}
+			if len(tt.expectedErrors) == 0 && errOut.Len() > 0 {
+				t.Errorf("expected no errors, got:\n%s", errOut.String())
+			}
+		})
+	}
+}
+
+func TestRunCheckSingleSecret(t *testing.T) {
+	tests := []struct {
+		name             string
+		svcErr           error
+		expectedExitCode int
+		expectedOutput   string
+	}{
+		{
+			name:             "Should fail like a listing when the secret can not be parsed",
+			svcErr:           fmt.Errorf("%w: no certificates found in input", service.ErrUnparsable),
+			expectedExitCode: cli.ExitCritical,
+			expectedOutput:   "1 invalid",
+		},
+		{
+			name:             "Should fail with an error exit code if the secret can not be read",
+			svcErr:           errTest,
+			expectedExitCode: cli.ExitError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewMockSecretService(nil, nil, func(ctx context.Context, namespace, name string) (service.TLSSecretInspection, error) {
+				return service.TLSSecretInspection{}, tt.svcErr
+			}, nil, nil, nil, nil)
+
+			var out, errOut bytes.Buffer
+			exitCode := cli.RunCheck(context.Background(), svc, cli.CheckOptions{Namespace: "default", Name: "broken"}, &out, &errOut)
+
+			if exitCode != tt.expectedExitCode {
+				t.Errorf("expected exit code %d, got %d (errors: %s)", tt.expectedExitCode, exitCode, errOut.String())
+			}
+			if !strings.Contains(out.String(), tt.expectedOutput) {
+				t.Errorf("expected output to contain %q, got:\n%s", tt.expectedOutput, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/codechamp1/certlens/internal/export"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+type ExportOptions struct {
+	Namespace string
+	Name      string
+	Format    export.Format
+}
+
+// RunExport writes the inventory selected by opts to w and returns the process exit code. The
+// secrets of reachable clusters are exported even if other clusters fail, with ExitError.
+func RunExport(ctx context.Context, svc service.SecretsService, opts ExportOptions, w io.Writer, errW io.Writer) int {
+	inspections, partial, err := inspect(ctx, svc, opts.Namespace, opts.Name)
+	if err != nil {
+		_, _ = fmt.Fprintf(errW, "Error: %v\n", err)
+		return ExitError
+	}
+	reportPartial(partial, errW)
+
+	if err := export.Write(w, opts.Format, inspections); err != nil {
+		_, _ = fmt.Fprintf(errW, "Error: can not export inventory: %v\n", err)
+		return ExitError
+	}
+
+	if partial != nil {
+		return ExitError
+	}
+	return ExitOK
+}
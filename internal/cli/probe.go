@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+type ProbeOptions struct {
+	Namespace string
+	Name      string
+	Target    string
+}
+
+// RunProbe writes the chain served by the target and its fingerprint diff against the secret
+// selected by opts to w and errors to errW. It returns ExitCritical when the endpoint serves
+// another chain.
+func RunProbe(ctx context.Context, svc service.SecretsService, opts ProbeOptions, w io.Writer, errW io.Writer) int {
+	report, err := svc.ProbeTLSSecret(ctx, opts.Namespace, opts.Name, opts.Target)
+	if err != nil {
+		_, _ = fmt.Fprintf(errW, "Error: %v\n", err)
+		return ExitError
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "#\tSUBJECT\tISSUER\tNOT AFTER\tREMAINING")
+	for i, cert := range report.Certificates {
+		_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", i+1, cert.Subject, cert.Issuer, cert.NotAfter, formatRemaining(time.Duration(cert.TimeUntilExpiry)))
+	}
+	if err := tw.Flush(); err != nil {
+		return ExitError
+	}
+
+	if len(report.Fingerprints) > 0 {
+		_, _ = fmt.Fprintf(w, "\nSHA-256 fingerprints, served by %s and stored in %s/%s:\n", report.Target, opts.Namespace, opts.Name)
+		for _, diff := range report.Fingerprints {
+			result := "match"
+			if !diff.Match() {
+				result = "differs"
+			}
+			served, stored := diff.Fingerprints()
+			_, _ = fmt.Fprintf(w, "  #%d %s\n    served: %s\n    stored: %s\n", diff.Position+1, result, served, stored)
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "\n%s: %s\n", report.Target, report.Status)
+
+	if report.Status == service.ProbeLeafMismatch || report.Status == service.ProbeChainMismatch {
+		return ExitCritical
+	}
+	return ExitOK
+}
@@ -0,0 +1,75 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codechamp1/certlens/internal/cli"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+func TestRunProbe(t *testing.T) {
+	tests := []struct {
+		name             string
+		report           service.ProbeReport
+		svcErr           error
+		expectedExitCode int
+		expectedOutput   []string
+		expectedErrOut   string
+	}{
+		{
+			name:             "Should fail with an error exit code if the endpoint can not be probed",
+			svcErr:           errTest,
+			expectedExitCode: cli.ExitError,
+			expectedErrOut:   "Error: simulated error",
+		},
+		{
+			name: "Should succeed when the endpoint serves the secret",
+			report: service.ProbeReport{
+				Target:       "example.com:443",
+				Status:       service.ProbeMatch,
+				Certificates: []service.CertificateInfo{certExpiringIn(30*24*time.Hour, service.StatusWarning)},
+				Fingerprints: []service.FingerprintDiff{{Served: "AA", Stored: "AA"}},
+			},
+			expectedExitCode: cli.ExitOK,
+			expectedOutput:   []string{"CN=localhost", "#1 match", "example.com:443: Match"},
+		},
+		{
+			name: "Should fail when the endpoint serves another certificate",
+			report: service.ProbeReport{
+				Target:       "example.com:443",
+				Status:       service.ProbeLeafMismatch,
+				Certificates: []service.CertificateInfo{certExpiringIn(30*24*time.Hour, service.StatusWarning)},
+				Fingerprints: []service.FingerprintDiff{{Served: "AA", Stored: "BB"}, {Position: 1, Stored: "CC"}},
+			},
+			expectedExitCode: cli.ExitCritical,
+			expectedOutput:   []string{"#1 differs", "served: AA", "stored: BB", "#2 differs", "served: -", service.ProbeLeafMismatch},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewMockSecretService(nil, nil, nil, nil, nil, nil, func(ctx context.Context, namespace, name, target string) (service.ProbeReport, error) {
+				return tt.report, tt.svcErr
+			})
+
+			var out, errOut bytes.Buffer
+			exitCode := cli.RunProbe(context.Background(), svc, cli.ProbeOptions{Namespace: "default", Name: "web-tls", Target: "example.com:443"}, &out, &errOut)
+
+			if exitCode != tt.expectedExitCode {
+				t.Errorf("expected exit code %d, got %d", tt.expectedExitCode, exitCode)
+			}
+			for _, expected := range tt.expectedOutput {
+				if !strings.Contains(out.String(), expected) {
+					t.Errorf("expected output to contain %q, got:\n%s", expected, out.String())
+				}
+			}
+			if !strings.Contains(errOut.String(), tt.expectedErrOut) || (tt.expectedErrOut == "" && errOut.Len() > 0) {
+				t.Errorf("expected error output %q, got %q", tt.expectedErrOut, errOut.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/codechamp1/certlens/internal/metrics"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+type ServeMetricsOptions struct {
+	Namespace     string
+	ListenAddress string
+	Interval      time.Duration
+}
+
+// RunServeMetrics exposes certificate metrics on /metrics until the process is terminated or
+// ctx is done.
+func RunServeMetrics(ctx context.Context, svc service.SecretsService, opts ServeMetricsOptions, errW io.Writer) int {
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	exporter := metrics.NewExporter(svc, opts.Namespace)
+	go exporter.Run(ctx, opts.Interval)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", exporter.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := &http.Server{
+		Addr:              opts.ListenAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		_, _ = fmt.Fprintf(errW, "Error: can not serve metrics: %v\n", err)
+		return ExitError
+	}
+
+	return ExitOK
+}
@@ -1,26 +1,60 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
+// secretsPageSize bounds the secrets returned by a single List call, large clusters are
+// listed in chunks.
+const secretsPageSize = 500
+
+// pemMarker starts every PEM block, see trimSecret.
+var pemMarker = []byte("-----BEGIN ")
+
+// keystoreMagics start the Java KeyStores (JKS and JCEKS), see trimSecret.
+var keystoreMagics = [][]byte{{0xFE, 0xED, 0xFE, 0xED}, {0xCE, 0xCE, 0xCE, 0xCE}}
+
+// tlsSecretsSelector lets the API server drop all secrets but TLS secrets.
+var tlsSecretsSelector = fields.OneTermEqualSelector("type", string(corev1.SecretTypeTLS)).String()
+
 type Client struct {
 	clientset kubernetes.Interface
+	dynamic   dynamic.Interface
+	config    *rest.Config
+	timeout   time.Duration // bounds every single request, 0 waits forever
 }
 
 type SecretsFetcher interface {
-	FetchSecrets(namespace string) (*corev1.SecretList, error)
-	FetchSecret(namespace, name string) (*corev1.Secret, error)
+	FetchSecrets(ctx context.Context, namespace string) (*corev1.SecretList, error)
+	FetchSecretsPages(ctx context.Context, namespace string, page func(*corev1.SecretList) error) error
+	FetchTLSSecretsPages(ctx context.Context, namespace string, page func(*corev1.SecretList) error) error
+	FetchSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error)
+	WatchSecrets(ctx context.Context, namespace string) (<-chan SecretEvent, error)
 }
 
-func newClient(kubeconfig, context string) (*Client, error) {
+// SecretEvent is a change of a TLS secret observed after the initial listing. WatchSecrets
+// returns once that listing completed, so a listing started afterwards misses no change.
+type SecretEvent struct {
+	Type   watch.EventType
+	Secret *corev1.Secret
+}
+
+func newClient(kubeconfig, context string, timeout time.Duration) (*Client, error) {
 	config, err := buildConfigWithContext(context, kubeconfig)
 
 	if err != nil {
@@ -33,13 +67,30 @@ func newClient(kubeconfig, context string) (*Client, error) {
 		return nil, fmt.Errorf("cant build the k8s client with the used kubeconfig: %w", err)
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(config)
+
+	if err != nil {
+		return nil, fmt.Errorf("cant build the k8s dynamic client with the used kubeconfig: %w", err)
+	}
+
 	return &Client{
 		clientset: clientset,
+		dynamic:   dynamicClient,
+		config:    config,
+		timeout:   timeout,
 	}, nil
 }
 
-func NewSecretsFetcher(kubeconfig, context string) (SecretsFetcher, error) {
-	client, err := newClient(kubeconfig, context)
+// requestContext bounds a single API request by the request timeout of the client.
+func (c Client) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+func NewSecretsFetcher(kubeconfig, context string, timeout time.Duration) (SecretsFetcher, error) {
+	client, err := newClient(kubeconfig, context, timeout)
 
 	if err != nil {
 		return nil, fmt.Errorf("error creating client: %w", err)
@@ -48,18 +99,59 @@ func NewSecretsFetcher(kubeconfig, context string) (SecretsFetcher, error) {
 	return client, nil
 }
 
-func (c Client) FetchSecrets(namespace string) (*corev1.SecretList, error) {
-	secrets, err := c.clientset.CoreV1().Secrets(namespace).List(context.TODO(), metav1.ListOptions{})
+// FetchSecrets lists the secrets of all types in the namespace.
+func (c Client) FetchSecrets(ctx context.Context, namespace string) (*corev1.SecretList, error) {
+	secrets := &corev1.SecretList{}
+	err := c.FetchSecretsPages(ctx, namespace, func(page *corev1.SecretList) error {
+		secrets.Items = append(secrets.Items, page.Items...)
+		return nil
+	})
 
 	if err != nil {
-		return nil, fmt.Errorf("error listing secrets in namespace %s: %w", namespace, err)
+		return nil, err
 	}
 
 	return secrets, nil
 }
 
-func (c Client) FetchSecret(namespace, name string) (*corev1.Secret, error) {
-	secret, err := c.clientset.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+// FetchSecretsPages lists the secrets of every type in chunks, like FetchTLSSecretsPages.
+func (c Client) FetchSecretsPages(ctx context.Context, namespace string, page func(*corev1.SecretList) error) error {
+	return c.listSecrets(ctx, namespace, "", page)
+}
+
+// FetchTLSSecretsPages lists the TLS secrets of the namespace in chunks of secretsPageSize and
+// calls page for every chunk as it arrives. Listing stops at the first error returned by page.
+// The request timeout bounds every chunk, not the whole listing.
+func (c Client) FetchTLSSecretsPages(ctx context.Context, namespace string, page func(*corev1.SecretList) error) error {
+	return c.listSecrets(ctx, namespace, tlsSecretsSelector, page)
+}
+
+func (c Client) listSecrets(ctx context.Context, namespace, fieldSelector string, page func(*corev1.SecretList) error) error {
+	options := metav1.ListOptions{FieldSelector: fieldSelector, Limit: secretsPageSize}
+	for {
+		requestCtx, cancel := c.requestContext(ctx)
+		secrets, err := c.clientset.CoreV1().Secrets(namespace).List(requestCtx, options)
+		cancel()
+
+		if err != nil {
+			return fmt.Errorf("error listing secrets in namespace %s: %w", namespace, err)
+		}
+
+		if err := page(secrets); err != nil {
+			return err
+		}
+
+		if secrets.Continue == "" {
+			return nil
+		}
+		options.Continue = secrets.Continue
+	}
+}
+
+func (c Client) FetchSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+	secret, err := c.clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
 
 	if err != nil {
 		return nil, fmt.Errorf("error fetching secret %s in namespace %s: %w", name, namespace, err)
@@ -68,16 +160,128 @@ func (c Client) FetchSecret(namespace, name string) (*corev1.Secret, error) {
 	return secret, nil
 }
 
-func buildConfigWithContext(context string, kubeconfigPath string) (*rest.Config, error) {
-	var loadingRules *clientcmd.ClientConfigLoadingRules
-	if kubeconfigPath != "" {
-		loadingRules = &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigPath}
-	} else {
-		loadingRules = clientcmd.NewDefaultClientConfigLoadingRules()
+// WatchSecrets streams add, update and delete events of TLS secrets in the namespace until ctx is done.
+// It returns once the initial listing completed, secrets that already exist at that point are not reported.
+// The initial listing is bounded by the request timeout. The returned channel is closed after ctx is done.
+func (c Client) WatchSecrets(ctx context.Context, namespace string) (<-chan SecretEvent, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stop := ctx.Done()
+	factory := informers.NewSharedInformerFactoryWithOptions(c.clientset, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = tlsSecretsSelector
+		}),
+	)
+	informer := factory.Core().V1().Secrets().Informer()
+	if err := informer.SetTransform(trimSecret); err != nil {
+		cancel()
+		return nil, fmt.Errorf("error watching secrets in namespace %s: %w", namespace, err)
 	}
+
+	events := make(chan SecretEvent)
+	send := func(eventType watch.EventType, obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		secret, ok := obj.(*corev1.Secret)
+		if !ok {
+			return
+		}
+		select {
+		case events <- SecretEvent{Type: eventType, Secret: secret}:
+		case <-stop:
+		}
+	}
+
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if !isInInitialList {
+				send(watch.Added, obj)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldSecret, okOld := oldObj.(*corev1.Secret)
+			newSecret, okNew := newObj.(*corev1.Secret)
+			if okOld && okNew && oldSecret.ResourceVersion == newSecret.ResourceVersion {
+				return // periodic resync, nothing changed
+			}
+			send(watch.Modified, newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			send(watch.Deleted, obj)
+		},
+	})
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("error watching secrets in namespace %s: %w", namespace, err)
+	}
+
+	factory.Start(stop)
+	syncCtx, cancelSync := c.requestContext(ctx)
+	defer cancelSync()
+	if !cache.WaitForCacheSync(syncCtx.Done(), informer.HasSynced) {
+		cancel()
+		factory.Shutdown()
+		if errors.Is(syncCtx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("error watching secrets in namespace %s: initial listing timed out after %s", namespace, c.timeout)
+		}
+		return nil, fmt.Errorf("error watching secrets in namespace %s: watch stopped before the initial listing completed", namespace)
+	}
+
+	go func() {
+		defer cancel()
+		<-stop
+		factory.Shutdown()
+		close(events)
+	}()
+
+	return events, nil
+}
+
+// trimSecret keeps only what a secret is summarized from in the informer cache: the managed
+// fields, the last applied configuration and the values of keys that can not hold certificates
+// are dropped.
+func trimSecret(obj interface{}) (interface{}, error) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return obj, nil
+	}
+
+	secret.ManagedFields = nil
+	delete(secret.Annotations, corev1.LastAppliedConfigAnnotation)
+	for key, value := range secret.Data {
+		if key != corev1.TLSCertKey && key != corev1.TLSPrivateKeyKey && !mayHoldCertificates(value) {
+			delete(secret.Data, key)
+		}
+	}
+	return secret, nil
+}
+
+// mayHoldCertificates cheaply tells apart values that can hold certificates: PEM, DER and
+// PKCS#12 (both ASN.1 sequences) and Java KeyStores. The repository parses them.
+func mayHoldCertificates(value []byte) bool {
+	if bytes.Contains(value, pemMarker) || (len(value) > 0 && value[0] == 0x30) {
+		return true
+	}
+	for _, magic := range keystoreMagics {
+		if bytes.HasPrefix(value, magic) {
+			return true
+		}
+	}
+	return false
+}
+
+func buildConfigWithContext(context string, kubeconfigPath string) (*rest.Config, error) {
 	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-		loadingRules,
+		loadingRules(kubeconfigPath),
 		&clientcmd.ConfigOverrides{
 			CurrentContext: context,
 		}).ClientConfig()
 }
+
+func loadingRules(kubeconfigPath string) *clientcmd.ClientConfigLoadingRules {
+	if kubeconfigPath != "" {
+		return &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigPath}
+	}
+	return clientcmd.NewDefaultClientConfigLoadingRules()
+}
@@ -1,12 +1,18 @@
 package client
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/fake"
 	k8sTesting "k8s.io/client-go/testing"
 )
@@ -50,8 +56,8 @@ func TestFetchSecrets(t *testing.T) {
 				})
 			}
 
-			client := &Client{k8sClient}
-			secrets, err := client.FetchSecrets(tt.namespace)
+			client := &Client{clientset: k8sClient}
+			secrets, err := client.FetchSecrets(context.Background(), tt.namespace)
 
 			if !errors.Is(err, tt.expectedErr) {
 				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
@@ -64,6 +70,64 @@ func TestFetchSecrets(t *testing.T) {
 	}
 }
 
+func TestClient_FetchTLSSecretsPages(t *testing.T) {
+	pages := map[string]*corev1.SecretList{
+		"": {
+			ListMeta: metav1.ListMeta{Continue: "page-2"},
+			Items:    []corev1.Secret{{ObjectMeta: metav1.ObjectMeta{Name: "first"}}},
+		},
+		"page-2": {
+			Items: []corev1.Secret{{ObjectMeta: metav1.ObjectMeta{Name: "second"}}, {ObjectMeta: metav1.ObjectMeta{Name: "third"}}},
+		},
+	}
+
+	k8sClient := fake.NewClientset()
+	var options []metav1.ListOptions
+	k8sClient.PrependReactor("list", "secrets", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		listOptions := action.(k8sTesting.ListActionImpl).ListOptions
+		options = append(options, listOptions)
+		return true, pages[listOptions.Continue], nil
+	})
+
+	client := &Client{clientset: k8sClient}
+	var names []string
+	err := client.FetchTLSSecretsPages(context.Background(), "default", func(page *corev1.SecretList) error {
+		for _, secret := range page.Items {
+			names = append(names, secret.Name)
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(names) != 3 {
+		t.Errorf("expected the secrets of both pages, got %v", names)
+	}
+	if len(options) != 2 || options[1].Continue != "page-2" {
+		t.Fatalf("expected the second page to be requested with the continue token, got %v", options)
+	}
+	for _, option := range options {
+		if option.FieldSelector != "type=kubernetes.io/tls" || option.Limit != secretsPageSize {
+			t.Errorf("expected a TLS field selector and a page limit, got %+v", option)
+		}
+	}
+
+	t.Run("Should stop listing at the first page error", func(t *testing.T) {
+		options = nil
+		err := client.FetchTLSSecretsPages(context.Background(), "default", func(page *corev1.SecretList) error {
+			return errTest
+		})
+
+		if !errors.Is(err, errTest) {
+			t.Errorf("expected error %v, got %v", errTest, err)
+		}
+		if len(options) != 1 {
+			t.Errorf("expected a single list call, got %d", len(options))
+		}
+	})
+}
+
 func TestClient_FetchSecret(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -102,8 +166,8 @@ func TestClient_FetchSecret(t *testing.T) {
 				})
 			}
 
-			client := &Client{k8sClient}
-			secret, err := client.FetchSecret(tt.namespace, tt.secretName)
+			client := &Client{clientset: k8sClient}
+			secret, err := client.FetchSecret(context.Background(), tt.namespace, tt.secretName)
 
 			if !errors.Is(err, tt.expectedErr) {
 				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
@@ -115,3 +179,316 @@ func TestClient_FetchSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_WatchSecrets(t *testing.T) {
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"},
+		Type:       corev1.SecretTypeTLS,
+	}
+	k8sClient := fake.NewClientset(existing)
+	client := &Client{clientset: k8sClient}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := client.WatchSecrets(ctx, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: "default", ResourceVersion: "1"},
+		Type:       corev1.SecretTypeTLS,
+	}
+	secrets := k8sClient.CoreV1().Secrets("default")
+
+	expectEvent := func(expectedType watch.EventType) {
+		t.Helper()
+		select {
+		case event := <-events:
+			if event.Type != expectedType || event.Secret.Name != "secret" {
+				t.Errorf("expected %s event for secret, got %s for %s", expectedType, event.Type, event.Secret.Name)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s event", expectedType)
+		}
+	}
+
+	if _, err := secrets.Create(context.TODO(), secret, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create secret: %v", err)
+	}
+	expectEvent(watch.Added)
+
+	secret.ResourceVersion = "2"
+	if _, err := secrets.Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update secret: %v", err)
+	}
+	expectEvent(watch.Modified)
+
+	if err := secrets.Delete(context.TODO(), secret.Name, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("failed to delete secret: %v", err)
+	}
+	expectEvent(watch.Deleted)
+
+	cancel()
+	for range events {
+		// drain until the watch shuts down and closes the channel
+	}
+}
+
+func TestClient_WatchSecretsTimeout(t *testing.T) {
+	k8sClient := fake.NewClientset()
+	k8sClient.PrependReactor("list", "secrets", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errTest
+	})
+	client := &Client{clientset: k8sClient, timeout: 200 * time.Millisecond}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.WatchSecrets(ctx, "default"); err == nil || ctx.Err() != nil {
+		t.Errorf("expected the initial listing to time out before the context, got %v", err)
+	}
+}
+
+func TestTrimSecret(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "secret",
+			Annotations:   map[string]string{corev1.LastAppliedConfigAnnotation: "{}", "cert-manager.io/certificate-name": "web"},
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte("-----BEGIN CERTIFICATE-----"),
+			corev1.TLSPrivateKeyKey: []byte("not pem"),
+			"ca.crt":                []byte("-----BEGIN CERTIFICATE-----"),
+			"keystore.jks":          {0xFE, 0xED, 0xFE, 0xED, 0, 0, 0, 2},
+			"ca.der":                {0x30, 0x82},
+			"password":              []byte("binary"),
+		},
+	}
+
+	trimmed, err := trimSecret(secret)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := trimmed.(*corev1.Secret)
+	if got.ManagedFields != nil || len(got.Annotations) != 1 {
+		t.Errorf("expected the managed fields and the last applied configuration to be dropped, got %+v", got.ObjectMeta)
+	}
+	if len(got.Data) != 5 || got.Data["password"] != nil {
+		t.Errorf("expected only the keys that can hold certificates to be kept, got %v", got.Data)
+	}
+}
+
+func TestClient_FetchResources(t *testing.T) {
+	certificates := schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+	certificate := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cert-manager.io/v1",
+		"kind":       "Certificate",
+		"metadata":   map[string]interface{}{"name": "cert", "namespace": "default"},
+		"spec":       map[string]interface{}{"secretName": "tls-secret"},
+	}}
+
+	tests := []struct {
+		name          string
+		namespace     string
+		expectedErr   error
+		expectedCount int
+	}{
+		{
+			name:        "Should return error if the client fails to list the resources",
+			namespace:   "default",
+			expectedErr: errTest,
+		},
+		{
+			name:          "Should list the resources of the namespace",
+			namespace:     "default",
+			expectedCount: 1,
+		},
+		{
+			name:          "Should not list the resources of other namespaces",
+			namespace:     "other",
+			expectedCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{certificates: "CertificateList"}, certificate.DeepCopy())
+			if tt.expectedErr != nil {
+				dynamicClient.PrependReactor("list", "certificates", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tt.expectedErr
+				})
+			}
+
+			client := &Client{dynamic: dynamicClient}
+			resources, err := client.FetchResources(context.Background(), certificates, tt.namespace)
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if tt.expectedErr == nil && len(resources.Items) != tt.expectedCount {
+				t.Errorf("expected %d resources, got %d", tt.expectedCount, len(resources.Items))
+			}
+		})
+	}
+}
+
+func TestClient_FetchConfigMapsPages(t *testing.T) {
+	pages := map[string]*corev1.ConfigMapList{
+		"": {
+			ListMeta: metav1.ListMeta{Continue: "page-2"},
+			Items:    []corev1.ConfigMap{{ObjectMeta: metav1.ObjectMeta{Name: "first"}}},
+		},
+		"page-2": {
+			Items: []corev1.ConfigMap{{ObjectMeta: metav1.ObjectMeta{Name: "second"}}},
+		},
+	}
+
+	k8sClient := fake.NewClientset()
+	var options []metav1.ListOptions
+	k8sClient.PrependReactor("list", "configmaps", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		listOptions := action.(k8sTesting.ListActionImpl).ListOptions
+		options = append(options, listOptions)
+		return true, pages[listOptions.Continue], nil
+	})
+
+	client := &Client{clientset: k8sClient}
+	var calls int
+	err := client.FetchConfigMapsPages(context.Background(), "default", func(page *corev1.ConfigMapList) error {
+		calls++
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 || len(options) != 2 || options[1].Continue != "page-2" || options[0].Limit != secretsPageSize {
+		t.Errorf("expected two limited pages, got %d pages for %+v", calls, options)
+	}
+}
+
+func TestClient_FetchConfigMaps(t *testing.T) {
+	tests := []struct {
+		name        string
+		configMaps  []runtime.Object
+		expectedErr error
+	}{
+		{
+			name:        "Should return error if the client fails to fetch configmaps",
+			expectedErr: errTest,
+		},
+		{
+			name: "Should return the configmaps of the namespace",
+			configMaps: []runtime.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "kube-root-ca.crt", Namespace: "default"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := fake.NewClientset(tt.configMaps...)
+			if tt.expectedErr != nil {
+				k8sClient.PrependReactor("list", "configmaps", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tt.expectedErr
+				})
+			}
+
+			client := &Client{clientset: k8sClient}
+			configMaps, err := client.FetchConfigMaps(context.Background(), "default")
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if tt.expectedErr == nil && len(configMaps.Items) != len(tt.configMaps) {
+				t.Errorf("expected %d configmaps, got %d", len(tt.configMaps), len(configMaps.Items))
+			}
+		})
+	}
+}
+
+func TestClient_FetchNamespaces(t *testing.T) {
+	tests := []struct {
+		name        string
+		namespaces  []runtime.Object
+		expectedErr error
+	}{
+		{
+			name:        "Should return error if the client fails to fetch namespaces",
+			expectedErr: errTest,
+		},
+		{
+			name: "Should return all namespaces",
+			namespaces: []runtime.Object{
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := fake.NewClientset(tt.namespaces...)
+			if tt.expectedErr != nil {
+				k8sClient.PrependReactor("list", "namespaces", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tt.expectedErr
+				})
+			}
+
+			client := &Client{clientset: k8sClient}
+			namespaces, err := client.FetchNamespaces(context.Background())
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if tt.expectedErr == nil && len(namespaces.Items) != len(tt.namespaces) {
+				t.Errorf("expected %d namespaces, got %d", len(tt.namespaces), len(namespaces.Items))
+			}
+		})
+	}
+}
+
+func TestClient_RequestContext(t *testing.T) {
+	t.Run("timeout bounds the request", func(t *testing.T) {
+		client := &Client{timeout: time.Minute}
+
+		ctx, cancel := client.requestContext(context.Background())
+		defer cancel()
+
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Fatal("expected a deadline")
+		}
+		if remaining := time.Until(deadline); remaining > time.Minute || remaining < 50*time.Second {
+			t.Errorf("expected a deadline in about a minute, got %s", remaining)
+		}
+	})
+
+	t.Run("zero timeout waits forever", func(t *testing.T) {
+		client := &Client{}
+
+		ctx, cancel := client.requestContext(context.Background())
+		defer cancel()
+
+		if _, ok := ctx.Deadline(); ok {
+			t.Error("expected no deadline")
+		}
+	})
+
+	t.Run("cancelled parent stops the request", func(t *testing.T) {
+		client := &Client{timeout: time.Minute}
+		parent, cancelParent := context.WithCancel(context.Background())
+		cancelParent()
+
+		ctx, cancel := client.requestContext(parent)
+		defer cancel()
+
+		if !errors.Is(ctx.Err(), context.Canceled) {
+			t.Errorf("expected the request to be cancelled, got %v", ctx.Err())
+		}
+	})
+}
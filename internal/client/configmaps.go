@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConfigMapsFetcher reads ConfigMaps, which often carry CA bundles such as kube-root-ca.crt.
+type ConfigMapsFetcher interface {
+	FetchConfigMaps(ctx context.Context, namespace string) (*corev1.ConfigMapList, error)
+	FetchConfigMapsPages(ctx context.Context, namespace string, page func(*corev1.ConfigMapList) error) error
+	FetchConfigMap(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error)
+}
+
+func NewConfigMapsFetcher(kubeconfig, context string, timeout time.Duration) (ConfigMapsFetcher, error) {
+	client, err := newClient(kubeconfig, context, timeout)
+
+	if err != nil {
+		return nil, fmt.Errorf("error creating client: %w", err)
+	}
+
+	return client, nil
+}
+
+func (c Client) FetchConfigMaps(ctx context.Context, namespace string) (*corev1.ConfigMapList, error) {
+	configMaps := &corev1.ConfigMapList{}
+	err := c.FetchConfigMapsPages(ctx, namespace, func(page *corev1.ConfigMapList) error {
+		configMaps.Items = append(configMaps.Items, page.Items...)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return configMaps, nil
+}
+
+// FetchConfigMapsPages lists the ConfigMaps of the namespace in chunks of secretsPageSize and
+// calls page for every chunk as it arrives, like FetchTLSSecretsPages.
+func (c Client) FetchConfigMapsPages(ctx context.Context, namespace string, page func(*corev1.ConfigMapList) error) error {
+	options := metav1.ListOptions{Limit: secretsPageSize}
+	for {
+		requestCtx, cancel := c.requestContext(ctx)
+		configMaps, err := c.clientset.CoreV1().ConfigMaps(namespace).List(requestCtx, options)
+		cancel()
+
+		if err != nil {
+			return fmt.Errorf("error listing configmaps in namespace %s: %w", namespace, err)
+		}
+
+		if err := page(configMaps); err != nil {
+			return err
+		}
+
+		if configMaps.Continue == "" {
+			return nil
+		}
+		options.Continue = configMaps.Continue
+	}
+}
+
+func (c Client) FetchConfigMap(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+	configMap, err := c.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+
+	if err != nil {
+		return nil, fmt.Errorf("error fetching configmap %s in namespace %s: %w", name, namespace, err)
+	}
+
+	return configMap, nil
+}
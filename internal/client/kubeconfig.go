@@ -0,0 +1,23 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// KubeconfigContexts returns the names of all contexts of the kubeconfig, sorted.
+func KubeconfigContexts(kubeconfig string) ([]string, error) {
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules(kubeconfig), &clientcmd.ConfigOverrides{}).RawConfig()
+	if err != nil {
+		return nil, fmt.Errorf("can not load the kubeconfig: %w", err)
+	}
+
+	contexts := make([]string, 0, len(config.Contexts))
+	for name := range config.Contexts {
+		contexts = append(contexts, name)
+	}
+	sort.Strings(contexts)
+	return contexts, nil
+}
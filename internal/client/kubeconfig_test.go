@@ -0,0 +1,49 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: prod
+  cluster: {server: "https://prod.example.com"}
+- name: staging
+  cluster: {server: "https://staging.example.com"}
+users:
+- name: admin
+  user: {token: secret}
+contexts:
+- name: staging
+  context: {cluster: staging, user: admin}
+- name: prod
+  context: {cluster: prod, user: admin}
+current-context: prod
+`
+
+func TestKubeconfigContexts(t *testing.T) {
+	t.Run("Should return all contexts sorted", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config")
+		if err := os.WriteFile(path, []byte(testKubeconfig), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		contexts, err := KubeconfigContexts(path)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if expected := []string{"prod", "staging"}; !reflect.DeepEqual(contexts, expected) {
+			t.Errorf("expected contexts %v, got %v", expected, contexts)
+		}
+	})
+
+	t.Run("Should return error if the kubeconfig can not be read", func(t *testing.T) {
+		if _, err := KubeconfigContexts(filepath.Join(t.TempDir(), "missing")); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
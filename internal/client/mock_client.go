@@ -1,26 +1,139 @@
 package client
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	"context"
+	"crypto/x509"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
 
 type mockSecretsFetcher struct {
-	mockFetchSecrets func(namespace string) (*corev1.SecretList, error)
-	mockFetchSecret  func(namespace, name string) (*corev1.Secret, error)
+	mockFetchSecrets func(ctx context.Context, namespace string) (*corev1.SecretList, error)
+	mockFetchSecret  func(ctx context.Context, namespace, name string) (*corev1.Secret, error)
+	mockWatchSecrets func(ctx context.Context, namespace string) (<-chan SecretEvent, error)
 }
 
 func NewMockSecretsFetcher(
-	mockGetTLSSecrets func(namespace string) (*corev1.SecretList, error),
-	mockGetTLSSecret func(namespace, name string) (*corev1.Secret, error),
+	mockGetTLSSecrets func(ctx context.Context, namespace string) (*corev1.SecretList, error),
+	mockGetTLSSecret func(ctx context.Context, namespace, name string) (*corev1.Secret, error),
+	mockWatchSecrets func(ctx context.Context, namespace string) (<-chan SecretEvent, error),
 ) SecretsFetcher {
 	return mockSecretsFetcher{
 		mockFetchSecrets: mockGetTLSSecrets,
 		mockFetchSecret:  mockGetTLSSecret,
+		mockWatchSecrets: mockWatchSecrets,
+	}
+}
+
+func (m mockSecretsFetcher) FetchSecrets(ctx context.Context, namespace string) (*corev1.SecretList, error) {
+	return m.mockFetchSecrets(ctx, namespace)
+}
+
+// FetchSecretsPages returns the secrets of the FetchSecrets mock as a single page.
+func (m mockSecretsFetcher) FetchSecretsPages(ctx context.Context, namespace string, page func(*corev1.SecretList) error) error {
+	return m.FetchTLSSecretsPages(ctx, namespace, page)
+}
+
+// FetchTLSSecretsPages returns the secrets of the FetchSecrets mock as a single page.
+func (m mockSecretsFetcher) FetchTLSSecretsPages(ctx context.Context, namespace string, page func(*corev1.SecretList) error) error {
+	secrets, err := m.mockFetchSecrets(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return page(secrets)
+}
+
+func (m mockSecretsFetcher) FetchSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+	return m.mockFetchSecret(ctx, namespace, name)
+}
+
+func (m mockSecretsFetcher) WatchSecrets(ctx context.Context, namespace string) (<-chan SecretEvent, error) {
+	return m.mockWatchSecrets(ctx, namespace)
+}
+
+type mockResourceFetcher struct {
+	mockFetchResources func(ctx context.Context, resource schema.GroupVersionResource, namespace string) (*unstructured.UnstructuredList, error)
+}
+
+func NewMockResourceFetcher(
+	mockFetchResources func(ctx context.Context, resource schema.GroupVersionResource, namespace string) (*unstructured.UnstructuredList, error),
+) ResourceFetcher {
+	return mockResourceFetcher{
+		mockFetchResources: mockFetchResources,
 	}
 }
 
-func (m mockSecretsFetcher) FetchSecrets(namespace string) (*corev1.SecretList, error) {
-	return m.mockFetchSecrets(namespace)
+func (m mockResourceFetcher) FetchResources(ctx context.Context, resource schema.GroupVersionResource, namespace string) (*unstructured.UnstructuredList, error) {
+	return m.mockFetchResources(ctx, resource, namespace)
+}
+
+type mockConfigMapsFetcher struct {
+	mockFetchConfigMaps func(ctx context.Context, namespace string) (*corev1.ConfigMapList, error)
+	mockFetchConfigMap  func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error)
+}
+
+func NewMockConfigMapsFetcher(
+	mockFetchConfigMaps func(ctx context.Context, namespace string) (*corev1.ConfigMapList, error),
+	mockFetchConfigMap func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error),
+) ConfigMapsFetcher {
+	return mockConfigMapsFetcher{
+		mockFetchConfigMaps: mockFetchConfigMaps,
+		mockFetchConfigMap:  mockFetchConfigMap,
+	}
+}
+
+func (m mockConfigMapsFetcher) FetchConfigMaps(ctx context.Context, namespace string) (*corev1.ConfigMapList, error) {
+	return m.mockFetchConfigMaps(ctx, namespace)
+}
+
+// FetchConfigMapsPages returns the ConfigMaps of the FetchConfigMaps mock as a single page.
+func (m mockConfigMapsFetcher) FetchConfigMapsPages(ctx context.Context, namespace string, page func(*corev1.ConfigMapList) error) error {
+	configMaps, err := m.mockFetchConfigMaps(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return page(configMaps)
+}
+
+func (m mockConfigMapsFetcher) FetchConfigMap(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	return m.mockFetchConfigMap(ctx, namespace, name)
+}
+
+type mockTLSProber struct {
+	mockProbeTLS        func(ctx context.Context, address, serverName string) ([]*x509.Certificate, error)
+	mockProbeServiceTLS func(ctx context.Context, namespace, service string, port int, serverName string) ([]*x509.Certificate, error)
+}
+
+func NewMockTLSProber(
+	mockProbeTLS func(ctx context.Context, address, serverName string) ([]*x509.Certificate, error),
+	mockProbeServiceTLS func(ctx context.Context, namespace, service string, port int, serverName string) ([]*x509.Certificate, error),
+) TLSProber {
+	return mockTLSProber{
+		mockProbeTLS:        mockProbeTLS,
+		mockProbeServiceTLS: mockProbeServiceTLS,
+	}
+}
+
+func (m mockTLSProber) ProbeTLS(ctx context.Context, address, serverName string) ([]*x509.Certificate, error) {
+	return m.mockProbeTLS(ctx, address, serverName)
+}
+
+func (m mockTLSProber) ProbeServiceTLS(ctx context.Context, namespace, service string, port int, serverName string) ([]*x509.Certificate, error) {
+	return m.mockProbeServiceTLS(ctx, namespace, service, port, serverName)
+}
+
+type mockNamespacesFetcher struct {
+	mockFetchNamespaces func(ctx context.Context) (*corev1.NamespaceList, error)
+}
+
+func NewMockNamespacesFetcher(mockFetchNamespaces func(ctx context.Context) (*corev1.NamespaceList, error)) NamespacesFetcher {
+	return mockNamespacesFetcher{
+		mockFetchNamespaces: mockFetchNamespaces,
+	}
 }
 
-func (m mockSecretsFetcher) FetchSecret(namespace, name string) (*corev1.Secret, error) {
-	return m.mockFetchSecret(namespace, name)
+func (m mockNamespacesFetcher) FetchNamespaces(ctx context.Context) (*corev1.NamespaceList, error) {
+	return m.mockFetchNamespaces(ctx)
 }
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NamespacesFetcher lists the namespaces the TUI can switch to.
+type NamespacesFetcher interface {
+	FetchNamespaces(ctx context.Context) (*corev1.NamespaceList, error)
+}
+
+func NewNamespacesFetcher(kubeconfig, context string, timeout time.Duration) (NamespacesFetcher, error) {
+	client, err := newClient(kubeconfig, context, timeout)
+
+	if err != nil {
+		return nil, fmt.Errorf("error creating client: %w", err)
+	}
+
+	return client, nil
+}
+
+func (c Client) FetchNamespaces(ctx context.Context) (*corev1.NamespaceList, error) {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+	namespaces, err := c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing namespaces: %w", err)
+	}
+
+	return namespaces, nil
+}
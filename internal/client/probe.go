@@ -0,0 +1,171 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+const probeTimeout = 10 * time.Second
+
+var errNoCluster = errors.New("probing a Service requires a cluster")
+
+// TLSProber captures the certificate chain a TLS endpoint presents in its handshake.
+type TLSProber interface {
+	// ProbeTLS dials address (host:port) with serverName as SNI.
+	ProbeTLS(ctx context.Context, address, serverName string) ([]*x509.Certificate, error)
+	// ProbeServiceTLS port-forwards to a ready pod backing the Service port and dials it.
+	ProbeServiceTLS(ctx context.Context, namespace, service string, port int, serverName string) ([]*x509.Certificate, error)
+}
+
+func NewTLSProber(kubeconfig, context string, timeout time.Duration) (TLSProber, error) {
+	client, err := newClient(kubeconfig, context, timeout)
+
+	if err != nil {
+		return nil, fmt.Errorf("error creating client: %w", err)
+	}
+
+	return client, nil
+}
+
+// NewLocalTLSProber returns a prober without a cluster, it can only dial host:port endpoints.
+func NewLocalTLSProber() TLSProber {
+	return Client{}
+}
+
+func (c Client) ProbeTLS(ctx context.Context, address, serverName string) ([]*x509.Certificate, error) {
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: probeTimeout},
+		// the presented chain is inspected, not trusted, so it is captured even if it does not verify
+		Config: &tls.Config{ServerName: serverName, InsecureSkipVerify: true}, //nolint:gosec
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("error dialing %s: %w", address, err)
+	}
+	defer conn.Close()
+
+	return conn.(*tls.Conn).ConnectionState().PeerCertificates, nil
+}
+
+func (c Client) ProbeServiceTLS(ctx context.Context, namespace, service string, port int, serverName string) ([]*x509.Certificate, error) {
+	if c.config == nil {
+		return nil, errNoCluster
+	}
+
+	pod, podPort, err := c.servicePod(ctx, namespace, service, port)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(c.config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating port-forward transport: %w", err)
+	}
+	url := c.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").Namespace(namespace).Name(pod).SubResource("portforward").URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	stop, ready := make(chan struct{}), make(chan struct{})
+	defer close(stop)
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"}, []string{"0:" + strconv.Itoa(podPort)}, stop, ready, io.Discard, io.Discard)
+	if err != nil {
+		return nil, fmt.Errorf("error port-forwarding to pod %s in namespace %s: %w", pod, namespace, err)
+	}
+
+	errs := make(chan error, 1)
+	go func() { errs <- forwarder.ForwardPorts() }()
+	select {
+	case <-ready:
+	case err := <-errs:
+		return nil, fmt.Errorf("error port-forwarding to pod %s in namespace %s: %w", pod, namespace, err)
+	case <-time.After(probeTimeout):
+		return nil, fmt.Errorf("error port-forwarding to pod %s in namespace %s: timed out", pod, namespace)
+	case <-ctx.Done():
+		return nil, fmt.Errorf("error port-forwarding to pod %s in namespace %s: %w", pod, namespace, ctx.Err())
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil || len(ports) == 0 {
+		return nil, fmt.Errorf("error port-forwarding to pod %s in namespace %s: no local port", pod, namespace)
+	}
+	return c.ProbeTLS(ctx, net.JoinHostPort("127.0.0.1", strconv.Itoa(int(ports[0].Local))), serverName)
+}
+
+// servicePod picks a ready pod selected by the Service and resolves the Service port to the
+// container port, which may be referenced by name.
+func (c Client) servicePod(ctx context.Context, namespace, name string, port int) (string, int, error) {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+
+	service, err := c.clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", 0, fmt.Errorf("error fetching service %s in namespace %s: %w", name, namespace, err)
+	}
+	if len(service.Spec.Selector) == 0 {
+		return "", 0, fmt.Errorf("service %s in namespace %s has no selector", name, namespace)
+	}
+
+	var servicePort *corev1.ServicePort
+	for i := range service.Spec.Ports {
+		if int(service.Spec.Ports[i].Port) == port {
+			servicePort = &service.Spec.Ports[i]
+		}
+	}
+	if servicePort == nil {
+		return "", 0, fmt.Errorf("service %s in namespace %s has no port %d", name, namespace, port)
+	}
+
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(service.Spec.Selector).String(),
+	})
+	if err != nil {
+		return "", 0, fmt.Errorf("error listing pods of service %s in namespace %s: %w", name, namespace, err)
+	}
+
+	for _, pod := range pods.Items {
+		if !podReady(pod) {
+			continue
+		}
+		targetPort := servicePort.TargetPort
+		switch {
+		case targetPort.IntValue() != 0:
+			return pod.Name, targetPort.IntValue(), nil
+		case targetPort.StrVal == "":
+			return pod.Name, port, nil
+		}
+		for _, container := range pod.Spec.Containers {
+			for _, containerPort := range container.Ports {
+				if containerPort.Name == targetPort.StrVal {
+					return pod.Name, int(containerPort.ContainerPort), nil
+				}
+			}
+		}
+	}
+	return "", 0, fmt.Errorf("service %s in namespace %s has no ready pod serving port %d", name, namespace, port)
+}
+
+func podReady(pod corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestClient_ProbeTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+
+	t.Run("Should capture the chain presented by the endpoint", func(t *testing.T) {
+		chain, err := Client{}.ProbeTLS(context.Background(), server.Listener.Addr().String(), "example.com")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(chain) != 1 || !chain[0].Equal(server.Certificate()) {
+			t.Errorf("expected the server certificate, got %d certificates", len(chain))
+		}
+	})
+
+	t.Run("Should return error if the endpoint can not be dialed", func(t *testing.T) {
+		if _, err := (Client{}).ProbeTLS(context.Background(), "127.0.0.1:1", ""); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("Should not port-forward without a cluster", func(t *testing.T) {
+		if _, err := NewLocalTLSProber().ProbeServiceTLS(context.Background(), "default", "web", 443, ""); err != errNoCluster {
+			t.Errorf("expected error %v, got %v", errNoCluster, err)
+		}
+	})
+}
+
+func TestClient_ServicePod(t *testing.T) {
+	ready := corev1.PodStatus{
+		Phase:      corev1.PodRunning,
+		Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "web"},
+			Ports: []corev1.ServicePort{
+				{Port: 443, TargetPort: intstr.FromString("https")},
+				{Port: 8443, TargetPort: intstr.FromInt32(9443)},
+			},
+		},
+	}
+	pending := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-pending", Namespace: "default", Labels: map[string]string{"app": "web"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodPending},
+	}
+	running := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-running", Namespace: "default", Labels: map[string]string{"app": "web"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Ports: []corev1.ContainerPort{{Name: "https", ContainerPort: 8443}},
+		}}},
+		Status: ready,
+	}
+
+	tests := []struct {
+		name         string
+		port         int
+		expectedPort int
+		expectedErr  string
+	}{
+		{name: "Should resolve named target ports on a ready pod", port: 443, expectedPort: 8443},
+		{name: "Should resolve numeric target ports", port: 8443, expectedPort: 9443},
+		{name: "Should return error for ports the service does not expose", port: 80, expectedErr: "has no port 80"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{clientset: fake.NewClientset(service, pending, running)}
+			pod, port, err := client.servicePod(context.Background(), "default", "web", tt.port)
+
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Errorf("expected error containing %q, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if pod != "web-running" || port != tt.expectedPort {
+				t.Errorf("expected web-running:%d, got %s:%d", tt.expectedPort, pod, port)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ResourceFetcher lists resources without a typed client, such as the custom resources of cert-manager.
+type ResourceFetcher interface {
+	FetchResources(ctx context.Context, resource schema.GroupVersionResource, namespace string) (*unstructured.UnstructuredList, error)
+}
+
+func NewResourceFetcher(kubeconfig, context string, timeout time.Duration) (ResourceFetcher, error) {
+	client, err := newClient(kubeconfig, context, timeout)
+
+	if err != nil {
+		return nil, fmt.Errorf("error creating client: %w", err)
+	}
+
+	return client, nil
+}
+
+func (c Client) FetchResources(ctx context.Context, resource schema.GroupVersionResource, namespace string) (*unstructured.UnstructuredList, error) {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+	resources, err := c.dynamic.Resource(resource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing %s in namespace %s: %w", resource.GroupResource(), namespace, err)
+	}
+
+	return resources, nil
+}
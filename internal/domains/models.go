@@ -1,14 +1,174 @@
 package domains
 
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Kinds of the resources found by the certificate scanner and of the cluster-scoped objects
+// carrying a caBundle, TLS secrets have no kind.
+const (
+	KindSecret                         = "Secret"
+	KindConfigMap                      = "ConfigMap"
+	KindValidatingWebhookConfiguration = "ValidatingWebhookConfiguration"
+	KindMutatingWebhookConfiguration   = "MutatingWebhookConfiguration"
+	KindCustomResourceDefinition       = "CustomResourceDefinition"
+	KindAPIService                     = "APIService"
+)
+
+// FileSecretType is the Type of the certificates read from local files.
+const FileSecretType = "file"
+
+// CACertKey is the secret key holding the CA certificates of the issuer, next to tls.crt and tls.key.
+const CACertKey = "ca.crt"
+
 type SecretInfo struct {
 	Name      string
 	Namespace string
+	Kind      string
 	Type      string
 	TLSCert   []byte
 	TLSKey    []byte
+	CACert    []byte
+	// PEMData holds the other keys of the secret that contain certificates, such as truststores,
+	// as PEM.
+	PEMData map[string][]byte
+	// CertKey is the data key TLSCert was read from.
+	CertKey string
+	// Unsupported maps the data keys holding certificates that can not be read, such as
+	// PKCS#12 keystores, to the reason.
+	Unsupported map[string]string
+	// Labels of the secret or of the resource found by the scanner.
+	Labels map[string]string
+}
+
+func (s SecretInfo) ID() K8SResourceID {
+	return K8SResourceID{Name: s.Name, Namespace: s.Namespace, Kind: s.Kind}
 }
 
 type K8SResourceID struct {
 	Name      string
 	Namespace string
+	// Kind is empty for TLS secrets and set for resources found by the certificate scanner.
+	Kind string
+	// Cluster is the kubeconfig context the resource was read from, it is only set when
+	// several clusters are inspected at once.
+	Cluster string
+}
+
+// QualifiedNamespace is the namespace prefixed with the cluster, see SplitNamespace.
+func (id K8SResourceID) QualifiedNamespace() string {
+	return QualifyNamespace(id.Cluster, id.Namespace)
+}
+
+// QualifyNamespace prefixes namespace with cluster as cluster/namespace. Context names may
+// contain slashes, namespaces can not.
+func QualifyNamespace(cluster, namespace string) string {
+	if cluster == "" {
+		return namespace
+	}
+	return cluster + "/" + namespace
+}
+
+// SplitNamespace splits a namespace created by QualifyNamespace into cluster and namespace.
+func SplitNamespace(qualified string) (cluster, namespace string) {
+	if i := strings.LastIndex(qualified, "/"); i != -1 {
+		return qualified[:i], qualified[i+1:]
+	}
+	return "", qualified
+}
+
+// Ref identifies the resource within its namespace, as kind/name for scanned resources.
+func (id K8SResourceID) Ref() string {
+	if id.Kind == "" {
+		return id.Name
+	}
+	return id.Kind + "/" + id.Name
+}
+
+// ParseRef splits a reference created by K8SResourceID.Ref into kind and name.
+func ParseRef(ref string) (kind, name string) {
+	if kind, name, ok := strings.Cut(ref, "/"); ok {
+		return kind, name
+	}
+	return "", ref
+}
+
+type SecretEventType string
+
+const (
+	SecretAdded    SecretEventType = "Added"
+	SecretModified SecretEventType = "Modified"
+	SecretDeleted  SecretEventType = "Deleted"
+)
+
+type SecretEvent struct {
+	Type   SecretEventType
+	Secret SecretInfo
+}
+
+// Condition is a single status condition of a custom resource.
+type Condition struct {
+	Type    string
+	Status  string
+	Reason  string
+	Message string
+}
+
+type IssuerRef struct {
+	Name  string
+	Kind  string
+	Group string
+}
+
+// CertManagerCertificate is a certificates.cert-manager.io resource, issued into SecretName.
+type CertManagerCertificate struct {
+	K8SResourceID
+	SecretName  string
+	CommonName  string
+	DNSNames    []string
+	IssuerRef   IssuerRef
+	Revision    int
+	RenewalTime *time.Time
+	Conditions  []Condition
+}
+
+// CertManagerCertificateRequest is a certificaterequests.cert-manager.io resource created
+// for a single revision of a Certificate.
+type CertManagerCertificateRequest struct {
+	K8SResourceID
+	CertificateName string
+	Revision        int
+	Conditions      []Condition
+}
+
+// CertManagerIssuer is an Issuer or, with an empty namespace, a ClusterIssuer.
+type CertManagerIssuer struct {
+	K8SResourceID
+	Kind       string
+	Conditions []Condition
+}
+
+// SecretReference is a resource serving a TLS secret, such as an Ingress.
+type SecretReference struct {
+	K8SResourceID
+	Kind   string
+	Secret K8SResourceID
+	Hosts  []string
+}
+
+// PartialError is returned together with the results that could be read when some of their
+// sources failed, e.g. a cluster of several or the caBundles next to the secrets. Errs holds one
+// error per failed source.
+type PartialError struct {
+	Errs []error
+}
+
+func (e *PartialError) Error() string {
+	return errors.Join(e.Errs...).Error()
+}
+
+func (e *PartialError) Unwrap() []error {
+	return e.Errs
 }
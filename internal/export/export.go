@@ -0,0 +1,196 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+type Format string
+
+const (
+	JSON Format = "json"
+	YAML Format = "yaml"
+	CSV  Format = "csv"
+)
+
+var Formats = []Format{JSON, YAML, CSV}
+
+func ParseFormat(value string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(value, string(f)) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported export format %q, supported formats: json, yaml, csv", value)
+}
+
+// SecretRecord is the exported form of a single inspected secret.
+type SecretRecord struct {
+	Cluster        string                     `json:"cluster,omitempty"`
+	Namespace      string                     `json:"namespace"`
+	Name           string                     `json:"name"`
+	Kind           string                     `json:"kind,omitempty"`
+	DataKey        string                     `json:"dataKey,omitempty"`
+	Error          string                     `json:"error,omitempty"`
+	Chain          *service.ChainReport       `json:"chain,omitempty"`
+	CertManager    *service.CertManagerReport `json:"certManager,omitempty"`
+	CertManagerErr string                     `json:"certManagerError,omitempty"`
+	PrivateKey     *service.PrivateKeyInfo    `json:"privateKey,omitempty"`
+	Findings       []service.Finding          `json:"findings,omitempty"`
+	UsedBy         []string                   `json:"usedBy,omitempty"`
+	Bundles        []service.PEMBundle        `json:"bundles,omitempty"`
+	UncoveredHosts []string                   `json:"uncoveredHosts,omitempty"`
+	Certificates   []service.CertificateInfo  `json:"certificates,omitempty"`
+}
+
+func Records(inspections []service.TLSSecretInspection) []SecretRecord {
+	records := make([]SecretRecord, 0, len(inspections))
+	for _, inspection := range inspections {
+		record := SecretRecord{
+			Cluster:   inspection.Cluster,
+			Namespace: inspection.Namespace,
+			Name:      inspection.Name,
+			Kind:      inspection.Kind,
+		}
+		if inspection.Kind != "" {
+			record.DataKey = inspection.CertKey
+		}
+		if inspection.Err != nil {
+			record.Error = inspection.Err.Error()
+		} else {
+			chain := inspection.Chain
+			record.Chain = &chain
+			record.CertManager = inspection.CertManager
+			if inspection.CertManagerErr != nil {
+				record.CertManagerErr = inspection.CertManagerErr.Error()
+			}
+			record.PrivateKey = inspection.PrivateKey
+			record.Findings = inspection.Findings
+			record.UsedBy = inspection.UsedBy
+			record.Bundles = inspection.Bundles
+			record.UncoveredHosts = inspection.UncoveredHosts
+			record.Certificates = inspection.Certificates
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// Write serializes the inspections in the given format. JSON and YAML keep the nested
+// secret/certificate structure, CSV writes one row per certificate of every chain.
+func Write(w io.Writer, format Format, inspections []service.TLSSecretInspection) error {
+	records := Records(inspections)
+
+	switch format {
+	case JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	case YAML:
+		data, err := yaml.Marshal(records)
+		if err != nil {
+			return fmt.Errorf("can not marshal inventory to yaml: %w", err)
+		}
+		_, err = w.Write(data)
+		return err
+	case CSV:
+		return writeCSV(w, records)
+	default:
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// ref is the name of TLS secrets and kind/name of the resources found by the scanner.
+func (r SecretRecord) ref() string {
+	return domains.K8SResourceID{Name: r.Name, Kind: r.Kind}.Ref()
+}
+
+func writeCSV(w io.Writer, records []SecretRecord) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{"namespace", "name", "chainIndex", "error"}
+	header = append(header, csvColumns(service.CertificateRawInfo{})...)
+	header = append(header, csvColumns(service.CertificateComputedInfo{})...)
+	header = append(header, csvColumns(service.CertificateIdentifiers{})...)
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("can not write csv header: %w", err)
+	}
+
+	for _, record := range records {
+		if record.Error != "" {
+			row := make([]string, len(header))
+			copy(row, []string{domains.QualifyNamespace(record.Cluster, record.Namespace), record.ref(), "", record.Error})
+			if err := cw.Write(row); err != nil {
+				return fmt.Errorf("can not write csv row: %w", err)
+			}
+			continue
+		}
+
+		for i, cert := range record.Certificates {
+			row := []string{domains.QualifyNamespace(record.Cluster, record.Namespace), record.ref(), strconv.Itoa(i), ""}
+			row = append(row, csvValues(cert.CertificateRawInfo)...)
+			row = append(row, csvValues(cert.CertificateComputedInfo)...)
+			row = append(row, csvValues(cert.CertificateIdentifiers)...)
+			if err := cw.Write(row); err != nil {
+				return fmt.Errorf("can not write csv row: %w", err)
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvColumns(s interface{}) []string {
+	t := reflect.TypeOf(s)
+	columns := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if name := jsonName(t.Field(i)); name != "" {
+			columns = append(columns, name)
+		}
+	}
+	return columns
+}
+
+func csvValues(s interface{}) []string {
+	v := reflect.ValueOf(s)
+	t := v.Type()
+	values := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if jsonName(t.Field(i)) == "" {
+			continue
+		}
+
+		var str string
+		switch fv := v.Field(i).Interface().(type) {
+		case []string:
+			str = strings.Join(fv, "; ")
+		case service.Duration:
+			str = fv.String()
+		case float64:
+			str = strconv.FormatFloat(fv, 'f', 2, 64)
+		default:
+			str = fmt.Sprintf("%v", fv)
+		}
+		values = append(values, str)
+	}
+	return values
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,133 @@
+package export_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/export"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+var inspections = []service.TLSSecretInspection{
+	{
+		K8SResourceID: domains.K8SResourceID{Name: "tls-secret", Namespace: "default"},
+		Certificates: []service.CertificateInfo{
+			{
+				CertificateRawInfo: service.CertificateRawInfo{
+					Subject:  "CN=leaf",
+					DNSNames: []string{"a.example.com", "b.example.com"},
+				},
+				CertificateComputedInfo: service.CertificateComputedInfo{
+					TimeUntilExpiry: service.Duration(48 * time.Hour),
+					ExpiryStatus:    "OK",
+				},
+			},
+			{
+				CertificateRawInfo: service.CertificateRawInfo{Subject: "CN=intermediate"},
+			},
+		},
+		Chain: service.ChainReport{Status: "Valid", Trusted: true},
+	},
+	{
+		K8SResourceID: domains.K8SResourceID{Name: "broken", Namespace: "default"},
+		Err:           errors.New("can not parse TLS secret"),
+	},
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, value := range []string{"json", "YAML", "csv"} {
+		if _, err := export.ParseFormat(value); err != nil {
+			t.Errorf("expected %q to be supported, got %v", value, err)
+		}
+	}
+
+	if _, err := export.ParseFormat("xml"); err == nil {
+		t.Error("expected xml to be rejected")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Run("Should write nested json with stable field names", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := export.Write(&out, export.JSON, inspections); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var records []map[string]interface{}
+		if err := json.Unmarshal(out.Bytes(), &records); err != nil {
+			t.Fatalf("invalid json: %v", err)
+		}
+
+		cert := records[0]["certificates"].([]interface{})[0].(map[string]interface{})
+		if cert["raw"].(map[string]interface{})["subject"] != "CN=leaf" {
+			t.Errorf("expected raw.subject to be exported, got %v", cert)
+		}
+		if cert["computed"].(map[string]interface{})["expiryStatus"] != "OK" {
+			t.Errorf("expected computed.expiryStatus to be exported, got %v", cert)
+		}
+		if cert["computed"].(map[string]interface{})["timeUntilExpiry"] != "48h0m0s" {
+			t.Errorf("expected computed.timeUntilExpiry to be encoded like the csv, got %v", cert)
+		}
+		if records[1]["error"] != "can not parse TLS secret" {
+			t.Errorf("expected error to be exported, got %v", records[1])
+		}
+	})
+
+	t.Run("Should write yaml", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := export.Write(&out, export.YAML, inspections); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, expected := range []string{"name: tls-secret", "subject: CN=leaf", "status: Valid"} {
+			if !strings.Contains(out.String(), expected) {
+				t.Errorf("expected yaml to contain %q, got:\n%s", expected, out.String())
+			}
+		}
+	})
+
+	t.Run("Should write one csv row per chain element", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := export.Write(&out, export.CSV, inspections); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		rows, err := csv.NewReader(&out).ReadAll()
+		if err != nil {
+			t.Fatalf("invalid csv: %v", err)
+		}
+
+		if len(rows) != 4 {
+			t.Fatalf("expected header and 3 rows, got %d", len(rows))
+		}
+
+		column := func(name string) int {
+			for i, c := range rows[0] {
+				if c == name {
+					return i
+				}
+			}
+			t.Fatalf("missing column %q in %v", name, rows[0])
+			return -1
+		}
+
+		if rows[1][column("dnsNames")] != "a.example.com; b.example.com" {
+			t.Errorf("unexpected dnsNames %q", rows[1][column("dnsNames")])
+		}
+		if rows[1][column("timeUntilExpiry")] != "48h0m0s" {
+			t.Errorf("unexpected timeUntilExpiry %q", rows[1][column("timeUntilExpiry")])
+		}
+		if rows[2][column("chainIndex")] != "1" || rows[2][column("subject")] != "CN=intermediate" {
+			t.Errorf("unexpected second chain element %v", rows[2])
+		}
+		if rows[3][column("error")] != "can not parse TLS secret" {
+			t.Errorf("unexpected error row %v", rows[3])
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+// secretLabels identify a secret, cluster is the kubeconfig context and empty unless several
+// clusters are inspected at once.
+var secretLabels = []string{"cluster", "namespace", "secret"}
+
+var certLabels = []string{"cluster", "namespace", "secret", "chain_index", "subject_cn", "issuer"}
+
+// Exporter periodically inspects TLS secrets and exposes their state as Prometheus metrics.
+type Exporter struct {
+	svc       service.SecretsService
+	namespace string
+	registry  *prometheus.Registry
+
+	notAfter       *prometheus.Desc
+	remainingRatio *prometheus.Desc
+	keyMismatch    *prometheus.Desc
+	parseError     *prometheus.Desc
+	series         *snapshotCollector
+	lastRefresh    prometheus.Gauge
+	refreshErrors  prometheus.Counter
+}
+
+func NewExporter(svc service.SecretsService, namespace string) *Exporter {
+	e := &Exporter{
+		svc:       svc,
+		namespace: namespace,
+		registry:  prometheus.NewRegistry(),
+		notAfter: prometheus.NewDesc("certlens_cert_not_after_seconds",
+			"Expiry of the certificate as a unix timestamp.", certLabels, nil),
+		remainingRatio: prometheus.NewDesc("certlens_cert_validity_remaining_ratio",
+			"Remaining share of the certificate validity period, between 0 and 1.", certLabels, nil),
+		keyMismatch: prometheus.NewDesc("certlens_secret_key_mismatch",
+			"1 if tls.key does not belong to the leaf certificate in tls.crt, 0 otherwise.", secretLabels, nil),
+		parseError: prometheus.NewDesc("certlens_secret_parse_error",
+			"1 if the certificates of the secret can not be parsed, 0 otherwise.", secretLabels, nil),
+		lastRefresh: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "certlens_last_refresh_timestamp_seconds",
+			Help: "Unix timestamp of the last successful refresh.",
+		}),
+		refreshErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "certlens_refresh_errors_total",
+			Help: "Number of refreshes that failed to list the TLS secrets.",
+		}),
+	}
+	e.series = &snapshotCollector{descs: []*prometheus.Desc{e.notAfter, e.remainingRatio, e.keyMismatch, e.parseError}}
+
+	e.registry.MustRegister(e.series, e.lastRefresh, e.refreshErrors)
+	return e
+}
+
+// Refresh inspects the TLS secrets once and replaces the exposed series. The series are built
+// first and swapped in at once, so a scrape during a refresh sees the previous ones. When some
+// of several clusters or sources fail, the series of the others are still replaced and the
+// error is returned.
+func (e *Exporter) Refresh(ctx context.Context) error {
+	inspections, err := e.svc.InspectTLSSecrets(ctx, e.namespace)
+	var partial *domains.PartialError
+	if err != nil {
+		e.refreshErrors.Inc()
+		if !errors.As(err, &partial) {
+			return err
+		}
+	}
+
+	var series []prometheus.Metric
+	gauge := func(desc *prometheus.Desc, value float64, labels ...string) {
+		series = append(series, prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labels...))
+	}
+
+	for _, inspection := range inspections {
+		if inspection.Err != nil {
+			gauge(e.parseError, 1, inspection.Cluster, inspection.Namespace, inspection.Ref())
+			continue
+		}
+		gauge(e.parseError, 0, inspection.Cluster, inspection.Namespace, inspection.Ref())
+
+		mismatch := 0.0
+		if inspection.Certificates[0].KeyMatches == service.KeyPairMismatch.String() {
+			mismatch = 1
+		}
+		gauge(e.keyMismatch, mismatch, inspection.Cluster, inspection.Namespace, inspection.Ref())
+
+		for i, cert := range inspection.Certificates {
+			labels := []string{inspection.Cluster, inspection.Namespace, inspection.Ref(), strconv.Itoa(i), cert.SubjectCommonName, cert.Issuer}
+
+			gauge(e.notAfter, float64(cert.NotAfterTime.Unix()), labels...)
+			gauge(e.remainingRatio, max(cert.RemainingPercent, 0)/100, labels...)
+		}
+	}
+	e.series.metrics.Store(&series)
+
+	if partial != nil {
+		return partial
+	}
+	e.lastRefresh.SetToCurrentTime()
+	return nil
+}
+
+// snapshotCollector serves the series of the last complete refresh.
+type snapshotCollector struct {
+	descs   []*prometheus.Desc
+	metrics atomic.Pointer[[]prometheus.Metric]
+}
+
+func (c *snapshotCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range c.descs {
+		ch <- desc
+	}
+}
+
+func (c *snapshotCollector) Collect(ch chan<- prometheus.Metric) {
+	metrics := c.metrics.Load()
+	if metrics == nil {
+		return
+	}
+	for _, metric := range *metrics {
+		ch <- metric
+	}
+}
+
+func (e *Exporter) Handler() http.Handler {
+	return promhttp.HandlerFor(e.registry, promhttp.HandlerOpts{})
+}
+
+// Run refreshes the metrics every interval until the context is cancelled.
+func (e *Exporter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.Refresh(ctx); err != nil {
+			log.Printf("failed to refresh certificate metrics: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/metrics"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+var errTest = errors.New("simulated error")
+
+func TestExporter(t *testing.T) {
+	notAfter := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		inspections     []service.TLSSecretInspection
+		svcErr          error
+		expectedErr     error
+		expectedMetrics []string
+	}{
+		{
+			name:        "Should count failed refreshes",
+			svcErr:      errTest,
+			expectedErr: errTest,
+			expectedMetrics: []string{
+				"certlens_refresh_errors_total 1",
+			},
+		},
+		{
+			name: "Should expose certificate and secret metrics",
+			inspections: []service.TLSSecretInspection{
+				{
+					K8SResourceID: domains.K8SResourceID{Name: "tls-secret", Namespace: "default"},
+					Certificates: []service.CertificateInfo{
+						{
+							CertificateRawInfo: service.CertificateRawInfo{
+								SubjectCommonName: "leaf",
+								Issuer:            "CN=ca",
+								NotAfter:          notAfter.Format(time.RFC1123),
+								NotAfterTime:      notAfter,
+							},
+							CertificateComputedInfo: service.CertificateComputedInfo{
+								RemainingPercent: 25,
+								KeyMatches:       service.KeyPairMismatch.String(),
+							},
+						},
+					},
+				},
+				{
+					K8SResourceID: domains.K8SResourceID{Name: "broken", Namespace: "default"},
+					Err:           errTest,
+				},
+			},
+			expectedMetrics: []string{
+				`certlens_cert_not_after_seconds{chain_index="0",cluster="",issuer="CN=ca",namespace="default",secret="tls-secret",subject_cn="leaf"} 1.893456e+09`,
+				`certlens_cert_validity_remaining_ratio{chain_index="0",cluster="",issuer="CN=ca",namespace="default",secret="tls-secret",subject_cn="leaf"} 0.25`,
+				`certlens_secret_key_mismatch{cluster="",namespace="default",secret="tls-secret"} 1`,
+				`certlens_secret_parse_error{cluster="",namespace="default",secret="broken"} 1`,
+				`certlens_secret_parse_error{cluster="",namespace="default",secret="tls-secret"} 0`,
+			},
+		},
+		{
+			name: "Should expose the reachable clusters when others fail",
+			inspections: []service.TLSSecretInspection{
+				{
+					K8SResourceID: domains.K8SResourceID{Name: "broken", Namespace: "default", Cluster: "prod"},
+					Err:           errTest,
+				},
+			},
+			svcErr:      &domains.PartialError{Errs: []error{errTest}},
+			expectedErr: errTest,
+			expectedMetrics: []string{
+				`certlens_secret_parse_error{cluster="prod",namespace="default",secret="broken"} 1`,
+				"certlens_refresh_errors_total 1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewMockSecretService(nil, nil, nil, func(ctx context.Context, namespace string) ([]service.TLSSecretInspection, error) {
+				return tt.inspections, tt.svcErr
+			}, nil, nil, nil)
+
+			exporter := metrics.NewExporter(svc, "")
+			if err := exporter.Refresh(context.Background()); !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			server := httptest.NewServer(exporter.Handler())
+			defer server.Close()
+
+			resp, err := server.Client().Get(server.URL)
+			if err != nil {
+				t.Fatalf("failed to scrape metrics: %v", err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+
+			for _, expected := range tt.expectedMetrics {
+				if !strings.Contains(string(body), expected) {
+					t.Errorf("expected metrics to contain %q, got:\n%s", expected, body)
+				}
+			}
+		})
+	}
+}
+
+func TestExporterRefresh(t *testing.T) {
+	var inspections []service.TLSSecretInspection
+	var svcErr error
+	svc := service.NewMockSecretService(nil, nil, nil, func(ctx context.Context, namespace string) ([]service.TLSSecretInspection, error) {
+		return inspections, svcErr
+	}, nil, nil, nil)
+	exporter := metrics.NewExporter(svc, "")
+
+	scrape := func() string {
+		server := httptest.NewServer(exporter.Handler())
+		defer server.Close()
+
+		resp, err := server.Client().Get(server.URL)
+		if err != nil {
+			t.Fatalf("failed to scrape metrics: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	inspections = []service.TLSSecretInspection{
+		{K8SResourceID: domains.K8SResourceID{Name: "old", Namespace: "default"}, Err: errTest},
+	}
+	if err := exporter.Refresh(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("Should keep the series of the last refresh if a refresh fails", func(t *testing.T) {
+		svcErr = errTest
+		defer func() { svcErr = nil }()
+
+		if err := exporter.Refresh(context.Background()); !errors.Is(err, errTest) {
+			t.Errorf("expected error %v, got %v", errTest, err)
+		}
+		if body := scrape(); !strings.Contains(body, `secret="old"`) {
+			t.Errorf("expected the series of the last refresh, got:\n%s", body)
+		}
+	})
+
+	t.Run("Should replace the series of the last refresh", func(t *testing.T) {
+		inspections = []service.TLSSecretInspection{
+			{K8SResourceID: domains.K8SResourceID{Name: "new", Namespace: "default"}, Err: errTest},
+		}
+		if err := exporter.Refresh(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if body := scrape(); strings.Contains(body, `secret="old"`) || !strings.Contains(body, `secret="new"`) {
+			t.Errorf("expected only the series of the new refresh, got:\n%s", body)
+		}
+	})
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/codechamp1/certlens/internal/client"
+	"github.com/codechamp1/certlens/internal/domains"
+)
+
+var (
+	validatingWebhooksResource = schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "validatingwebhookconfigurations"}
+	mutatingWebhooksResource   = schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "mutatingwebhookconfigurations"}
+	crdsResource               = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	apiServicesResource        = schema.GroupVersionResource{Group: "apiregistration.k8s.io", Version: "v1", Resource: "apiservices"}
+)
+
+// caBundleSource maps the caBundles of a cluster-scoped kind.
+type caBundleSource struct {
+	kind     string
+	resource schema.GroupVersionResource
+	mapper   func(kind string, resource unstructured.Unstructured) []domains.SecretInfo
+}
+
+var caBundleSources = []caBundleSource{
+	{domains.KindValidatingWebhookConfiguration, validatingWebhooksResource, webhookCABundles},
+	{domains.KindMutatingWebhookConfiguration, mutatingWebhooksResource, webhookCABundles},
+	{domains.KindCustomResourceDefinition, crdsResource, conversionCABundle},
+	{domains.KindAPIService, apiServicesResource, apiServiceCABundle},
+}
+
+// caBundleRepository adds the caBundles of webhook configurations, CRD conversion webhooks and
+// APIServices to the secrets of another repository. Every caBundle is a resource of the owning
+// kind, webhooks are named "configuration/webhook". The objects are cluster-scoped, so they are
+// only listed together with all namespaces. Kinds that can not be listed, e.g. for lack of RBAC,
+// do not fail the listing: the secrets and the other caBundles are returned together with a
+// domains.PartialError naming them.
+type caBundleRepository struct {
+	SecretsRepository
+	client client.ResourceFetcher
+}
+
+func NewCABundleRepository(secrets SecretsRepository, client client.ResourceFetcher) SecretsRepository {
+	return caBundleRepository{
+		SecretsRepository: secrets,
+		client:            client,
+	}
+}
+
+func (c caBundleRepository) GetTLSSecrets(ctx context.Context, namespace string) ([]domains.SecretInfo, error) {
+	secrets, err := c.SecretsRepository.GetTLSSecrets(ctx, namespace)
+	if err != nil || namespace != "" {
+		return secrets, err
+	}
+
+	bundles, err := c.allCABundles(ctx)
+	return append(secrets, bundles...), err
+}
+
+// GetTLSSecretsPages streams the pages of the wrapped repository, the caBundles follow as the
+// last page.
+func (c caBundleRepository) GetTLSSecretsPages(ctx context.Context, namespace string, page func([]domains.SecretInfo) error) error {
+	if err := c.SecretsRepository.GetTLSSecretsPages(ctx, namespace, page); err != nil || namespace != "" {
+		return err
+	}
+
+	bundles, err := c.allCABundles(ctx)
+	if pageErr := page(bundles); pageErr != nil {
+		return pageErr
+	}
+	return err
+}
+
+// allCABundles lists the caBundles of every source, the sources that fail are returned as a
+// domains.PartialError together with the caBundles of the others.
+func (c caBundleRepository) allCABundles(ctx context.Context) ([]domains.SecretInfo, error) {
+	var all []domains.SecretInfo
+	var failed []error
+	for _, source := range caBundleSources {
+		bundles, err := c.caBundles(ctx, source)
+		if err != nil {
+			failed = append(failed, err)
+			continue
+		}
+		all = append(all, bundles...)
+	}
+	if len(failed) > 0 {
+		return all, &domains.PartialError{Errs: failed}
+	}
+	return all, nil
+}
+
+// GetTLSSecret returns a caBundle by its domains.K8SResourceID.Ref, any other name is looked up
+// in the wrapped repository.
+func (c caBundleRepository) GetTLSSecret(ctx context.Context, namespace, ref string) (domains.SecretInfo, error) {
+	kind, name := domains.ParseRef(ref)
+	for _, source := range caBundleSources {
+		if source.kind != kind {
+			continue
+		}
+		bundles, err := c.caBundles(ctx, source)
+		if err != nil {
+			return domains.SecretInfo{}, err
+		}
+		for _, bundle := range bundles {
+			if bundle.Name == name {
+				return bundle, nil
+			}
+		}
+		return domains.SecretInfo{}, fmt.Errorf("no caBundle found for %s %s", kind, name)
+	}
+	return c.SecretsRepository.GetTLSSecret(ctx, namespace, ref)
+}
+
+func (c caBundleRepository) caBundles(ctx context.Context, source caBundleSource) ([]domains.SecretInfo, error) {
+	resources, err := listResources(ctx, c.client, source.resource, "")
+	if err != nil {
+		return nil, fmt.Errorf("can not list the caBundles of %s: %w", source.kind, err)
+	}
+
+	var bundles []domains.SecretInfo
+	for _, resource := range resources {
+		bundles = append(bundles, source.mapper(source.kind, resource)...)
+	}
+	return bundles, nil
+}
+
+// webhookCABundles maps webhooks[].clientConfig.caBundle of admission webhook configurations.
+func webhookCABundles(kind string, configuration unstructured.Unstructured) []domains.SecretInfo {
+	webhooks, _, _ := unstructured.NestedSlice(configuration.Object, "webhooks")
+
+	var bundles []domains.SecretInfo
+	for _, entry := range webhooks {
+		webhook, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(webhook, "name")
+		caBundle, _, _ := unstructured.NestedString(webhook, "clientConfig", "caBundle")
+		path := fmt.Sprintf("webhooks[%s].clientConfig.caBundle", name)
+		if bundle, ok := newCABundle(kind, configuration.GetName()+"/"+name, path, caBundle); ok {
+			bundles = append(bundles, bundle)
+		}
+	}
+	return bundles
+}
+
+// conversionCABundle maps spec.conversion.webhook.clientConfig.caBundle of a CustomResourceDefinition.
+func conversionCABundle(kind string, crd unstructured.Unstructured) []domains.SecretInfo {
+	fields := []string{"spec", "conversion", "webhook", "clientConfig", "caBundle"}
+	caBundle, _, _ := unstructured.NestedString(crd.Object, fields...)
+	if bundle, ok := newCABundle(kind, crd.GetName(), strings.Join(fields, "."), caBundle); ok {
+		return []domains.SecretInfo{bundle}
+	}
+	return nil
+}
+
+// apiServiceCABundle maps spec.caBundle of an APIService.
+func apiServiceCABundle(kind string, apiService unstructured.Unstructured) []domains.SecretInfo {
+	caBundle, _, _ := unstructured.NestedString(apiService.Object, "spec", "caBundle")
+	if bundle, ok := newCABundle(kind, apiService.GetName(), "spec.caBundle", caBundle); ok {
+		return []domains.SecretInfo{bundle}
+	}
+	return nil
+}
+
+// newCABundle decodes a base64 encoded caBundle field. Empty caBundles, left to the system roots
+// or yet to be injected, are skipped. A caBundle that is not valid base64 is kept as is, so it is
+// reported as unparsable.
+func newCABundle(kind, name, path, caBundle string) (domains.SecretInfo, bool) {
+	if caBundle == "" {
+		return domains.SecretInfo{}, false
+	}
+
+	data, err := base64.StdEncoding.DecodeString(caBundle)
+	if err != nil {
+		data = []byte(caBundle)
+	}
+
+	return domains.SecretInfo{
+		Name:    name,
+		Kind:    kind,
+		CertKey: path,
+		TLSCert: data,
+	}, true
+}
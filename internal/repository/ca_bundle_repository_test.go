@@ -0,0 +1,165 @@
+package repository_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/codechamp1/certlens/internal/client"
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/repository"
+)
+
+func TestCABundleRepository(t *testing.T) {
+	cert := readFixture(t, "tls.crt")
+	caBundle := base64.StdEncoding.EncodeToString(cert)
+
+	resources := map[string]map[string]interface{}{
+		"validatingwebhookconfigurations": {
+			"metadata": map[string]interface{}{"name": "policy"},
+			"webhooks": []interface{}{
+				map[string]interface{}{"name": "validate.example.com", "clientConfig": map[string]interface{}{"caBundle": caBundle}},
+				map[string]interface{}{"name": "injected.example.com", "clientConfig": map[string]interface{}{}},
+			},
+		},
+		"mutatingwebhookconfigurations": {
+			"metadata": map[string]interface{}{"name": "defaults"},
+			"webhooks": []interface{}{
+				map[string]interface{}{"name": "mutate.example.com", "clientConfig": map[string]interface{}{"caBundle": caBundle}},
+			},
+		},
+		"customresourcedefinitions": {
+			"metadata": map[string]interface{}{"name": "widgets.example.com"},
+			"spec": map[string]interface{}{
+				"conversion": map[string]interface{}{
+					"strategy": "Webhook",
+					"webhook":  map[string]interface{}{"clientConfig": map[string]interface{}{"caBundle": caBundle}},
+				},
+			},
+		},
+		"apiservices": {
+			"metadata": map[string]interface{}{"name": "v1beta1.metrics.k8s.io"},
+			"spec":     map[string]interface{}{"caBundle": caBundle},
+		},
+	}
+
+	secrets := repository.NewMockRepository(
+		func(ctx context.Context, namespace string) ([]domains.SecretInfo, error) {
+			return []domains.SecretInfo{{Name: "web-tls", Namespace: "default"}}, nil
+		},
+		func(ctx context.Context, namespace, name string) (domains.SecretInfo, error) {
+			return domains.SecretInfo{Name: name, Namespace: namespace}, nil
+		},
+		nil,
+	)
+
+	newRepo := func(fetchErr error) repository.SecretsRepository {
+		return repository.NewCABundleRepository(secrets, client.NewMockResourceFetcher(func(ctx context.Context, resource schema.GroupVersionResource, namespace string) (*unstructured.UnstructuredList, error) {
+			if fetchErr != nil {
+				return nil, fetchErr
+			}
+			return &unstructured.UnstructuredList{Items: []unstructured.Unstructured{{Object: resources[resource.Resource]}}}, nil
+		}))
+	}
+
+	t.Run("Should list every caBundle with its owning object when listing all namespaces", func(t *testing.T) {
+		found, err := newRepo(nil).GetTLSSecrets(context.Background(), "")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		expected := []struct{ ref, key string }{
+			{"web-tls", ""},
+			{"ValidatingWebhookConfiguration/policy/validate.example.com", "webhooks[validate.example.com].clientConfig.caBundle"},
+			{"MutatingWebhookConfiguration/defaults/mutate.example.com", "webhooks[mutate.example.com].clientConfig.caBundle"},
+			{"CustomResourceDefinition/widgets.example.com", "spec.conversion.webhook.clientConfig.caBundle"},
+			{"APIService/v1beta1.metrics.k8s.io", "spec.caBundle"},
+		}
+		if len(found) != len(expected) {
+			t.Fatalf("expected %d resources, got %+v", len(expected), found)
+		}
+		for i, secret := range found {
+			if secret.ID().Ref() != expected[i].ref || secret.CertKey != expected[i].key {
+				t.Errorf("expected %s from %s, got %s from %s", expected[i].ref, expected[i].key, secret.ID().Ref(), secret.CertKey)
+			}
+			if secret.Kind != "" && !bytes.Equal(secret.TLSCert, cert) {
+				t.Errorf("expected the decoded caBundle of %s", secret.ID().Ref())
+			}
+		}
+	})
+
+	t.Run("Should stream the caBundles after the pages of secrets", func(t *testing.T) {
+		var pages [][]domains.SecretInfo
+		err := newRepo(nil).GetTLSSecretsPages(context.Background(), "", func(page []domains.SecretInfo) error {
+			pages = append(pages, page)
+			return nil
+		})
+		if err != nil || len(pages) != 2 {
+			t.Fatalf("expected a page of secrets and a page of caBundles, got %d pages, %v", len(pages), err)
+		}
+		if len(pages[0]) != 1 || len(pages[1]) != 4 {
+			t.Errorf("expected 1 secret and 4 caBundles, got %d and %d", len(pages[0]), len(pages[1]))
+		}
+	})
+
+	t.Run("Should only list the secrets of a namespace", func(t *testing.T) {
+		found, err := newRepo(nil).GetTLSSecrets(context.Background(), "default")
+		if err != nil || len(found) != 1 {
+			t.Errorf("expected only the secret of the namespace, got %+v, %v", found, err)
+		}
+	})
+
+	t.Run("Should get a caBundle by its ref and delegate other names", func(t *testing.T) {
+		repo := newRepo(nil)
+		bundle, err := repo.GetTLSSecret(context.Background(), "", "APIService/v1beta1.metrics.k8s.io")
+		if err != nil || bundle.Kind != domains.KindAPIService {
+			t.Errorf("expected the APIService caBundle, got %+v, %v", bundle, err)
+		}
+		if _, err := repo.GetTLSSecret(context.Background(), "", "ValidatingWebhookConfiguration/policy/injected.example.com"); err == nil {
+			t.Error("expected error for a webhook without caBundle, got nil")
+		}
+		secret, err := repo.GetTLSSecret(context.Background(), "default", "web-tls")
+		if err != nil || secret.Name != "web-tls" || secret.Kind != "" {
+			t.Errorf("expected the secret, got %+v, %v", secret, err)
+		}
+	})
+
+	t.Run("Should keep the secrets when the caBundles can not be listed", func(t *testing.T) {
+		forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "apiservices"}, "", errTest)
+		for _, fetchErr := range []error{forbidden, errTest} {
+			found, err := newRepo(fetchErr).GetTLSSecrets(context.Background(), "")
+			var partial *domains.PartialError
+			if !errors.As(err, &partial) || len(partial.Errs) != 4 || !errors.Is(err, fetchErr) {
+				t.Errorf("expected a partial error for each kind wrapping %v, got %v", fetchErr, err)
+			}
+			if len(found) != 1 || found[0].Name != "web-tls" {
+				t.Errorf("expected the secrets, got %+v", found)
+			}
+		}
+	})
+
+	t.Run("Should stream the secrets before failing the caBundles", func(t *testing.T) {
+		var found []domains.SecretInfo
+		err := newRepo(errTest).GetTLSSecretsPages(context.Background(), "", func(page []domains.SecretInfo) error {
+			found = append(found, page...)
+			return nil
+		})
+		var partial *domains.PartialError
+		if !errors.As(err, &partial) || len(found) != 1 {
+			t.Errorf("expected the secret and a partial error, got %+v, %v", found, err)
+		}
+	})
+
+	t.Run("Should return error if a caBundle can not be looked up", func(t *testing.T) {
+		_, err := newRepo(errTest).GetTLSSecret(context.Background(), "", "APIService/v1beta1.metrics.k8s.io")
+		if !errors.Is(err, errTest) {
+			t.Errorf("expected error %v, got %v", errTest, err)
+		}
+	})
+}
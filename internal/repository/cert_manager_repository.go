@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/codechamp1/certlens/internal/client"
+	"github.com/codechamp1/certlens/internal/domains"
+)
+
+const (
+	certificateNameAnnotation     = "cert-manager.io/certificate-name"
+	certificateRevisionAnnotation = "cert-manager.io/certificate-revision"
+)
+
+var (
+	certificatesResource        = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+	certificateRequestsResource = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificaterequests"}
+	issuersResource             = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "issuers"}
+	clusterIssuersResource      = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "clusterissuers"}
+)
+
+// CertManagerRepository reads the cert-manager resources. Clusters without cert-manager, or
+// where they are not visible to the user, yield no resources instead of an error.
+type CertManagerRepository interface {
+	GetCertificates(ctx context.Context, namespace string) ([]domains.CertManagerCertificate, error)
+	GetCertificateRequests(ctx context.Context, namespace string) ([]domains.CertManagerCertificateRequest, error)
+	// GetIssuers returns the Issuers of the namespace and all ClusterIssuers.
+	GetIssuers(ctx context.Context, namespace string) ([]domains.CertManagerIssuer, error)
+}
+
+type certManagerRepository struct {
+	client client.ResourceFetcher
+}
+
+func NewCertManagerRepository(client client.ResourceFetcher) CertManagerRepository {
+	return certManagerRepository{
+		client: client,
+	}
+}
+
+func (c certManagerRepository) GetCertificates(ctx context.Context, namespace string) ([]domains.CertManagerCertificate, error) {
+	resources, err := c.fetch(ctx, certificatesResource, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	certificates := make([]domains.CertManagerCertificate, 0, len(resources))
+	for _, resource := range resources {
+		certificates = append(certificates, mapCertificateToModel(resource))
+	}
+	return certificates, nil
+}
+
+func (c certManagerRepository) GetCertificateRequests(ctx context.Context, namespace string) ([]domains.CertManagerCertificateRequest, error) {
+	resources, err := c.fetch(ctx, certificateRequestsResource, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	requests := make([]domains.CertManagerCertificateRequest, 0, len(resources))
+	for _, resource := range resources {
+		annotations := resource.GetAnnotations()
+		revision, _ := strconv.Atoi(annotations[certificateRevisionAnnotation])
+		requests = append(requests, domains.CertManagerCertificateRequest{
+			K8SResourceID:   domains.K8SResourceID{Name: resource.GetName(), Namespace: resource.GetNamespace()},
+			CertificateName: annotations[certificateNameAnnotation],
+			Revision:        revision,
+			Conditions:      conditions(resource),
+		})
+	}
+	return requests, nil
+}
+
+func (c certManagerRepository) GetIssuers(ctx context.Context, namespace string) ([]domains.CertManagerIssuer, error) {
+	issuers, err := c.fetch(ctx, issuersResource, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterIssuers, err := c.fetch(ctx, clusterIssuersResource, "")
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]domains.CertManagerIssuer, 0, len(issuers)+len(clusterIssuers))
+	for _, resource := range append(issuers, clusterIssuers...) {
+		models = append(models, domains.CertManagerIssuer{
+			K8SResourceID: domains.K8SResourceID{Name: resource.GetName(), Namespace: resource.GetNamespace()},
+			Kind:          resource.GetKind(),
+			Conditions:    conditions(resource),
+		})
+	}
+	return models, nil
+}
+
+func (c certManagerRepository) fetch(ctx context.Context, resource schema.GroupVersionResource, namespace string) ([]unstructured.Unstructured, error) {
+	resources, err := listResources(ctx, c.client, resource, namespace)
+	if apierrors.IsForbidden(err) {
+		return nil, nil // cert-manager resources are not visible to us
+	}
+	return resources, err
+}
+
+// listResources lists the resources of an optional API, it returns no resources if the API
+// is not installed in the cluster.
+func listResources(ctx context.Context, client client.ResourceFetcher, resource schema.GroupVersionResource, namespace string) ([]unstructured.Unstructured, error) {
+	list, err := client.FetchResources(ctx, resource, namespace)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s in namespace %s: %w", resource.GroupResource(), namespace, err)
+	}
+	return list.Items, nil
+}
+
+func mapCertificateToModel(resource unstructured.Unstructured) domains.CertManagerCertificate {
+	secretName, _, _ := unstructured.NestedString(resource.Object, "spec", "secretName")
+	commonName, _, _ := unstructured.NestedString(resource.Object, "spec", "commonName")
+	dnsNames, _, _ := unstructured.NestedStringSlice(resource.Object, "spec", "dnsNames")
+	issuerName, _, _ := unstructured.NestedString(resource.Object, "spec", "issuerRef", "name")
+	issuerKind, _, _ := unstructured.NestedString(resource.Object, "spec", "issuerRef", "kind")
+	issuerGroup, _, _ := unstructured.NestedString(resource.Object, "spec", "issuerRef", "group")
+	revision, _, _ := unstructured.NestedInt64(resource.Object, "status", "revision")
+
+	certificate := domains.CertManagerCertificate{
+		K8SResourceID: domains.K8SResourceID{Name: resource.GetName(), Namespace: resource.GetNamespace()},
+		SecretName:    secretName,
+		CommonName:    commonName,
+		DNSNames:      dnsNames,
+		IssuerRef:     domains.IssuerRef{Name: issuerName, Kind: issuerKind, Group: issuerGroup},
+		Revision:      int(revision),
+		Conditions:    conditions(resource),
+	}
+
+	if renewal, ok, _ := unstructured.NestedString(resource.Object, "status", "renewalTime"); ok {
+		if renewalTime, err := time.Parse(time.RFC3339, renewal); err == nil {
+			certificate.RenewalTime = &renewalTime
+		}
+	}
+
+	return certificate
+}
+
+func conditions(resource unstructured.Unstructured) []domains.Condition {
+	items, _, _ := unstructured.NestedSlice(resource.Object, "status", "conditions")
+
+	var models []domains.Condition
+	for _, item := range items {
+		condition, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		model := domains.Condition{}
+		model.Type, _, _ = unstructured.NestedString(condition, "type")
+		model.Status, _, _ = unstructured.NestedString(condition, "status")
+		model.Reason, _, _ = unstructured.NestedString(condition, "reason")
+		model.Message, _, _ = unstructured.NestedString(condition, "message")
+		models = append(models, model)
+	}
+	return models
+}
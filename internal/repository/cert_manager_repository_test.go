@@ -0,0 +1,124 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/codechamp1/certlens/internal/client"
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/repository"
+)
+
+func TestGetCertificates(t *testing.T) {
+	certificate := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cert-manager.io/v1",
+		"kind":       "Certificate",
+		"metadata":   map[string]interface{}{"name": "example", "namespace": "default"},
+		"spec": map[string]interface{}{
+			"secretName": "example-tls",
+			"commonName": "example.com",
+			"dnsNames":   []interface{}{"example.com", "www.example.com"},
+			"issuerRef":  map[string]interface{}{"name": "letsencrypt", "kind": "ClusterIssuer", "group": "cert-manager.io"},
+		},
+		"status": map[string]interface{}{
+			"revision":    int64(3),
+			"renewalTime": "2030-01-01T00:00:00Z",
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True", "reason": "Ready", "message": "Certificate is up to date"},
+			},
+		},
+	}}
+	renewal := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		fetchErr             error
+		expectedCertificates []domains.CertManagerCertificate
+		expectedErr          bool
+	}{
+		{
+			name: "Should map the Certificate resources",
+			expectedCertificates: []domains.CertManagerCertificate{
+				{
+					K8SResourceID: domains.K8SResourceID{Name: "example", Namespace: "default"},
+					SecretName:    "example-tls",
+					CommonName:    "example.com",
+					DNSNames:      []string{"example.com", "www.example.com"},
+					IssuerRef:     domains.IssuerRef{Name: "letsencrypt", Kind: "ClusterIssuer", Group: "cert-manager.io"},
+					Revision:      3,
+					RenewalTime:   &renewal,
+					Conditions:    []domains.Condition{{Type: "Ready", Status: "True", Reason: "Ready", Message: "Certificate is up to date"}},
+				},
+			},
+		},
+		{
+			name:     "Should return no Certificates if cert-manager is not installed",
+			fetchErr: apierrors.NewNotFound(schema.GroupResource{Group: "cert-manager.io", Resource: "certificates"}, ""),
+		},
+		{
+			name:        "Should return error if the Certificates can not be listed",
+			fetchErr:    errTest,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := client.NewMockResourceFetcher(func(ctx context.Context, resource schema.GroupVersionResource, namespace string) (*unstructured.UnstructuredList, error) {
+				if tt.fetchErr != nil {
+					return nil, tt.fetchErr
+				}
+				return &unstructured.UnstructuredList{Items: []unstructured.Unstructured{certificate}}, nil
+			})
+
+			certificates, err := repository.NewCertManagerRepository(mockClient).GetCertificates(context.Background(), "default")
+
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("expected error: %v, got %v", tt.expectedErr, err)
+			}
+			if tt.expectedErr && !errors.Is(err, errTest) {
+				t.Errorf("expected error %v, got %v", errTest, err)
+			}
+
+			if len(certificates) != len(tt.expectedCertificates) || (len(certificates) > 0 && !reflect.DeepEqual(certificates, tt.expectedCertificates)) {
+				t.Errorf("expected certificates %+v, got %+v", tt.expectedCertificates, certificates)
+			}
+		})
+	}
+}
+
+func TestGetIssuers(t *testing.T) {
+	t.Run("Should return the Issuers of the namespace and all ClusterIssuers", func(t *testing.T) {
+		mockClient := client.NewMockResourceFetcher(func(ctx context.Context, resource schema.GroupVersionResource, namespace string) (*unstructured.UnstructuredList, error) {
+			kind, ns := "Issuer", namespace
+			if resource.Resource == "clusterissuers" {
+				kind, ns = "ClusterIssuer", ""
+			}
+			issuer := unstructured.Unstructured{}
+			issuer.SetKind(kind)
+			issuer.SetName("letsencrypt")
+			issuer.SetNamespace(ns)
+			return &unstructured.UnstructuredList{Items: []unstructured.Unstructured{issuer}}, nil
+		})
+
+		issuers, err := repository.NewCertManagerRepository(mockClient).GetIssuers(context.Background(), "default")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		expected := []domains.CertManagerIssuer{
+			{K8SResourceID: domains.K8SResourceID{Name: "letsencrypt", Namespace: "default"}, Kind: "Issuer"},
+			{K8SResourceID: domains.K8SResourceID{Name: "letsencrypt"}, Kind: "ClusterIssuer"},
+		}
+		if !reflect.DeepEqual(issuers, expected) {
+			t.Errorf("expected issuers %+v, got %+v", expected, issuers)
+		}
+	})
+}
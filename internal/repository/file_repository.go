@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/codechamp1/certlens/internal/domains"
+)
+
+// certFileExtensions are the extensions picked up when walking a directory.
+var certFileExtensions = map[string]bool{
+	".pem": true,
+	".crt": true,
+	".cer": true,
+	".der": true,
+	".jks": true,
+}
+
+var errWatchNotSupported = errors.New("watching is not supported for local files")
+
+// fileRepository serves certificates from the local filesystem. Every certificate file
+// is mapped to a secret named after the file in a "namespace" named after its directory.
+// tls.crt picks up tls.key and ca.crt next to it, any other file picks up a key file with
+// the same base name (server.crt and server.key) or private keys bundled in the file itself.
+// The certificates of Java KeyStores are read as well, PKCS#12 keystores are not.
+type fileRepository struct {
+	files []string
+	dirs  []string
+}
+
+func NewFileRepository(files, dirs []string) SecretsRepository {
+	return fileRepository{
+		files: files,
+		dirs:  dirs,
+	}
+}
+
+func (f fileRepository) GetTLSSecrets(ctx context.Context, namespace string) ([]domains.SecretInfo, error) {
+	secrets, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+
+	if namespace == "" {
+		return secrets, nil
+	}
+
+	var filtered []domains.SecretInfo
+	for _, secret := range secrets {
+		if secret.Namespace == namespace {
+			filtered = append(filtered, secret)
+		}
+	}
+	return filtered, nil
+}
+
+func (f fileRepository) GetTLSSecretsPages(ctx context.Context, namespace string, page func([]domains.SecretInfo) error) error {
+	secrets, err := f.GetTLSSecrets(ctx, namespace)
+	return singlePage(secrets, err, page)
+}
+
+func (f fileRepository) GetTLSSecret(ctx context.Context, namespace, name string) (domains.SecretInfo, error) {
+	secrets, err := f.load()
+	if err != nil {
+		return domains.SecretInfo{}, err
+	}
+
+	for _, secret := range secrets {
+		if secret.Name == name && (namespace == "" || secret.Namespace == namespace) {
+			return secret, nil
+		}
+	}
+	return domains.SecretInfo{}, fmt.Errorf("certificate file %s not found in %s", name, namespace)
+}
+
+func (f fileRepository) WatchTLSSecrets(context.Context, string) (<-chan domains.SecretEvent, error) {
+	return nil, errWatchNotSupported
+}
+
+// load reads the configured files and directories from scratch, so every call reflects
+// the current state of the filesystem.
+func (f fileRepository) load() ([]domains.SecretInfo, error) {
+	var secrets []domains.SecretInfo
+
+	for _, file := range f.files {
+		secret, ok, err := readCertFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("no certificates found in %s", file)
+		}
+		secrets = append(secrets, secret)
+	}
+
+	for _, dir := range f.dirs {
+		dirSecrets, err := readCertDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, dirSecrets...)
+	}
+
+	return secrets, nil
+}
+
+func readCertDir(root string) ([]domains.SecretInfo, error) {
+	var paths []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !certFileExtensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+		// ca.crt belongs to the tls.crt next to it
+		if d.Name() == domains.CACertKey && fileExists(filepath.Join(filepath.Dir(path), "tls.crt")) {
+			return nil
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", root, err)
+	}
+	sort.Strings(paths)
+
+	var secrets []domains.SecretInfo
+	for _, path := range paths {
+		secret, ok, err := readCertFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if ok { // skip files that only hold keys or other PEM data
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets, nil
+}
+
+// readCertFile maps a certificate file and its companion files to a secret. It reports
+// false if the file does not contain any certificate.
+func readCertFile(path string) (domains.SecretInfo, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return domains.SecretInfo{}, false, fmt.Errorf("failed to read certificate file %s: %w", path, err)
+	}
+
+	certs, keys := sniffCertificates(data)
+	if len(certs) == 0 && isPKCS12(data) {
+		return domains.SecretInfo{}, false, fmt.Errorf("can not read %s: %s", path, pkcs12Unsupported)
+	}
+	if len(certs) == 0 {
+		return domains.SecretInfo{}, false, nil
+	}
+
+	dir, name := filepath.Dir(path), filepath.Base(path)
+	secret := domains.SecretInfo{
+		Name:      name,
+		Namespace: dir,
+		Type:      domains.FileSecretType,
+		TLSCert:   certs,
+		TLSKey:    keys,
+	}
+
+	keyFile := strings.TrimSuffix(path, filepath.Ext(path)) + ".key"
+	if len(secret.TLSKey) == 0 && fileExists(keyFile) {
+		if secret.TLSKey, err = os.ReadFile(keyFile); err != nil {
+			return domains.SecretInfo{}, false, fmt.Errorf("failed to read key file %s: %w", keyFile, err)
+		}
+	}
+
+	if name == "tls.crt" {
+		caFile := filepath.Join(dir, domains.CACertKey)
+		if fileExists(caFile) {
+			if secret.CACert, err = os.ReadFile(caFile); err != nil {
+				return domains.SecretInfo{}, false, fmt.Errorf("failed to read CA file %s: %w", caFile, err)
+			}
+		}
+	}
+
+	return secret, true, nil
+}
+
+// sniffCertificates extracts the certificates and private keys of PEM data, falling back to
+// DER encoded certificates and to the certificates of Java KeyStores, whose keys are encrypted.
+// The certificates are returned PEM encoded. PKCS#12 keystores are not read, see isPKCS12.
+func sniffCertificates(data []byte) (certs, keys []byte) {
+	certs, keys = splitPEM(data)
+	if len(certs) > 0 {
+		return certs, keys
+	}
+
+	var der [][]byte
+	if parsed, err := x509.ParseCertificates(data); err == nil {
+		for _, cert := range parsed {
+			der = append(der, cert.Raw)
+		}
+	} else if stored, ok := jksCertificates(data); ok {
+		der = stored
+	}
+	for _, cert := range der {
+		certs = append(certs, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert})...)
+	}
+	return certs, keys
+}
+
+// splitPEM separates the certificate blocks of a PEM bundle from its private key blocks.
+func splitPEM(data []byte) (certs, keys []byte) {
+	var certBuf, keyBuf bytes.Buffer
+	for {
+		block, rest := pem.Decode(data)
+		if block == nil {
+			break
+		}
+		data = rest
+
+		switch {
+		case block.Type == "CERTIFICATE":
+			_ = pem.Encode(&certBuf, block)
+		case strings.HasSuffix(block.Type, "PRIVATE KEY"), block.Type == "EC PARAMETERS":
+			_ = pem.Encode(&keyBuf, block)
+		}
+	}
+	return certBuf.Bytes(), keyBuf.Bytes()
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,215 @@
+package repository_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/codechamp1/certlens/internal/repository"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "test", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return data
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// encodeJKS builds a version 2 Java KeyStore holding a trusted certificate entry per DER certificate.
+func encodeJKS(certs ...[]byte) []byte {
+	var buf bytes.Buffer
+	write := func(v any) { _ = binary.Write(&buf, binary.BigEndian, v) }
+	writeUTF := func(s string) {
+		write(uint16(len(s)))
+		buf.WriteString(s)
+	}
+
+	write(uint32(0xFEEDFEED))
+	write(uint32(2))
+	write(uint32(len(certs)))
+	for i, cert := range certs {
+		write(uint32(2))
+		writeUTF(strings.Repeat("a", i+1))
+		write(uint64(0))
+		writeUTF("X.509")
+		write(uint32(len(cert)))
+		buf.Write(cert)
+	}
+	buf.Write(make([]byte, 20)) // the keyed digest, not verified
+	return buf.Bytes()
+}
+
+// encodePKCS12 builds the outer structure of a PKCS#12 keystore with empty content.
+func encodePKCS12(t *testing.T) []byte {
+	t.Helper()
+	pfx := struct {
+		Version  int
+		AuthSafe struct {
+			ContentType asn1.ObjectIdentifier
+			Content     []byte `asn1:"tag:0,explicit"`
+		}
+	}{Version: 3}
+	pfx.AuthSafe.ContentType = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+
+	data, err := asn1.Marshal(pfx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestFileRepository(t *testing.T) {
+	cert, key := readFixture(t, "tls.crt"), readFixture(t, "tls.key")
+	block, _ := pem.Decode(cert)
+
+	root := t.TempDir()
+	secretDir := filepath.Join(root, "secret")
+	writeFile(t, filepath.Join(secretDir, "tls.crt"), cert)
+	writeFile(t, filepath.Join(secretDir, "tls.key"), key)
+	writeFile(t, filepath.Join(secretDir, "ca.crt"), cert)
+	writeFile(t, filepath.Join(root, "server.crt"), cert)
+	writeFile(t, filepath.Join(root, "server.key"), key)
+	writeFile(t, filepath.Join(root, "bundle.pem"), append(append([]byte{}, cert...), key...))
+	writeFile(t, filepath.Join(root, "cert.der"), block.Bytes)
+	writeFile(t, filepath.Join(root, "truststore.jks"), encodeJKS(block.Bytes, block.Bytes))
+	writeFile(t, filepath.Join(root, "keystore.p12"), encodePKCS12(t))
+	writeFile(t, filepath.Join(root, "key-only.pem"), key)
+	writeFile(t, filepath.Join(root, "notes.txt"), []byte("not a certificate"))
+
+	t.Run("Should map every certificate file of a directory to a secret", func(t *testing.T) {
+		repo := repository.NewFileRepository(nil, []string{root})
+
+		secrets, err := repo.GetTLSSecrets(context.Background(), "")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		var names []string
+		for _, secret := range secrets {
+			names = append(names, filepath.Join(secret.Namespace, secret.Name))
+			if !bytes.Contains(secret.TLSCert, []byte("BEGIN CERTIFICATE")) {
+				t.Errorf("expected %s to hold PEM certificates", secret.Name)
+			}
+		}
+
+		expected := []string{
+			filepath.Join(root, "bundle.pem"),
+			filepath.Join(root, "cert.der"),
+			filepath.Join(root, "secret", "tls.crt"),
+			filepath.Join(root, "server.crt"),
+			filepath.Join(root, "truststore.jks"),
+		}
+		if len(names) != len(expected) {
+			t.Fatalf("expected secrets %v, got %v", expected, names)
+		}
+		for i := range expected {
+			if names[i] != expected[i] {
+				t.Errorf("expected secret %s, got %s", expected[i], names[i])
+			}
+		}
+	})
+
+	t.Run("Should pick up companion key and CA files", func(t *testing.T) {
+		repo := repository.NewFileRepository(nil, []string{root})
+
+		tests := []struct {
+			namespace string
+			name      string
+			hasKey    bool
+			hasCA     bool
+		}{
+			{namespace: secretDir, name: "tls.crt", hasKey: true, hasCA: true},
+			{namespace: root, name: "server.crt", hasKey: true},
+			{namespace: root, name: "bundle.pem", hasKey: true},
+			{namespace: root, name: "cert.der"},
+		}
+
+		for _, tt := range tests {
+			secret, err := repo.GetTLSSecret(context.Background(), tt.namespace, tt.name)
+			if err != nil {
+				t.Fatalf("expected no error for %s, got %v", tt.name, err)
+			}
+			if (len(secret.TLSKey) > 0) != tt.hasKey {
+				t.Errorf("expected key of %s to be present: %v", tt.name, tt.hasKey)
+			}
+			if (len(secret.CACert) > 0) != tt.hasCA {
+				t.Errorf("expected CA of %s to be present: %v", tt.name, tt.hasCA)
+			}
+		}
+	})
+
+	t.Run("Should filter by directory", func(t *testing.T) {
+		repo := repository.NewFileRepository(nil, []string{root})
+
+		secrets, err := repo.GetTLSSecrets(context.Background(), secretDir)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(secrets) != 1 || secrets[0].Name != "tls.crt" {
+			t.Errorf("expected only tls.crt in %s, got %v", secretDir, secrets)
+		}
+	})
+
+	t.Run("Should fail for a single file without certificates", func(t *testing.T) {
+		repo := repository.NewFileRepository([]string{filepath.Join(root, "key-only.pem")}, nil)
+
+		if _, err := repo.GetTLSSecrets(context.Background(), ""); err == nil {
+			t.Error("expected an error, got nil")
+		}
+	})
+
+	t.Run("Should read every certificate of a Java KeyStore", func(t *testing.T) {
+		repo := repository.NewFileRepository([]string{filepath.Join(root, "truststore.jks")}, nil)
+
+		secret, err := repo.GetTLSSecret(context.Background(), root, "truststore.jks")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if count := bytes.Count(secret.TLSCert, []byte("BEGIN CERTIFICATE")); count != 2 {
+			t.Errorf("expected 2 certificates, got %d", count)
+		}
+	})
+
+	t.Run("Should fail for a PKCS#12 keystore", func(t *testing.T) {
+		repo := repository.NewFileRepository([]string{filepath.Join(root, "keystore.p12")}, nil)
+
+		_, err := repo.GetTLSSecrets(context.Background(), "")
+		if err == nil || !strings.Contains(err.Error(), "PKCS#12") {
+			t.Errorf("expected the PKCS#12 limitation, got %v", err)
+		}
+	})
+
+	t.Run("Should fail for a missing secret", func(t *testing.T) {
+		repo := repository.NewFileRepository([]string{filepath.Join(root, "server.crt")}, nil)
+
+		if _, err := repo.GetTLSSecret(context.Background(), root, "missing.crt"); err == nil {
+			t.Error("expected an error, got nil")
+		}
+	})
+
+	t.Run("Should not support watching", func(t *testing.T) {
+		repo := repository.NewFileRepository(nil, []string{root})
+
+		if _, err := repo.WatchTLSSecrets(context.Background(), ""); err == nil {
+			t.Error("expected an error, got nil")
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+const (
+	jksMagic   = 0xFEEDFEED
+	jceksMagic = 0xCECECECE
+
+	jksPrivateKeyEntry  = 1
+	jksTrustedCertEntry = 2
+)
+
+// pkcs12Unsupported is the reason PKCS#12 keystores are not inspected.
+const pkcs12Unsupported = "PKCS#12 keystores are password protected and can not be inspected"
+
+var (
+	oidData       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidSignedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+)
+
+var errJKSEntry = errors.New("unsupported keystore entry")
+
+// jksCertificates returns the DER certificates of a Java KeyStore (JKS or JCEKS). The
+// certificates of trusted certificate entries and the chains of private key entries are
+// stored in clear, so no password is needed. Reading stops at the first entry of another
+// type, e.g. a JCEKS secret key, returning the certificates read so far.
+func jksCertificates(data []byte) ([][]byte, bool) {
+	r := bytes.NewReader(data)
+	var magic, version, count uint32
+	if binary.Read(r, binary.BigEndian, &magic) != nil || (magic != jksMagic && magic != jceksMagic) {
+		return nil, false
+	}
+	if binary.Read(r, binary.BigEndian, &version) != nil || (version != 1 && version != 2) {
+		return nil, false
+	}
+	if binary.Read(r, binary.BigEndian, &count) != nil {
+		return nil, false
+	}
+
+	var certs [][]byte
+	for range count {
+		entryCerts, err := readJKSEntry(r, version)
+		certs = append(certs, entryCerts...)
+		if err != nil {
+			break
+		}
+	}
+	return certs, len(certs) > 0
+}
+
+func readJKSEntry(r *bytes.Reader, version uint32) ([][]byte, error) {
+	var tag uint32
+	if err := binary.Read(r, binary.BigEndian, &tag); err != nil {
+		return nil, err
+	}
+	if _, err := readJKSBlock(r, 2); err != nil { // alias
+		return nil, err
+	}
+	if _, err := r.Seek(8, io.SeekCurrent); err != nil { // creation date
+		return nil, err
+	}
+
+	chainLength := uint32(1)
+	switch tag {
+	case jksTrustedCertEntry:
+	case jksPrivateKeyEntry:
+		if _, err := readJKSBlock(r, 4); err != nil { // the encrypted key
+			return nil, err
+		}
+		if err := binary.Read(r, binary.BigEndian, &chainLength); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errJKSEntry
+	}
+
+	var certs [][]byte
+	for range chainLength {
+		if version == 2 {
+			if _, err := readJKSBlock(r, 2); err != nil { // certificate type, X.509
+				return certs, err
+			}
+		}
+		cert, err := readJKSBlock(r, 4)
+		if err != nil {
+			return certs, err
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// readJKSBlock reads a block prefixed with its big-endian length of lengthSize bytes.
+func readJKSBlock(r *bytes.Reader, lengthSize int) ([]byte, error) {
+	var length int
+	if lengthSize == 2 {
+		var n uint16
+		if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+			return nil, err
+		}
+		length = int(n)
+	} else {
+		var n uint32
+		if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+			return nil, err
+		}
+		length = int(n)
+	}
+	if length > r.Len() {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	block := make([]byte, length)
+	_, err := io.ReadFull(r, block)
+	return block, err
+}
+
+// isPKCS12 reports whether data is a PKCS#12 keystore (RFC 7292 PFX).
+func isPKCS12(data []byte) bool {
+	var pfx struct {
+		Version  int
+		AuthSafe struct {
+			ContentType asn1.ObjectIdentifier
+			Content     asn1.RawValue `asn1:"tag:0,explicit,optional"`
+		}
+		MacData asn1.RawValue `asn1:"optional"`
+	}
+	if _, err := asn1.Unmarshal(data, &pfx); err != nil {
+		return false
+	}
+	return pfx.Version == 3 && (pfx.AuthSafe.ContentType.Equal(oidData) || pfx.AuthSafe.ContentType.Equal(oidSignedData))
+}
@@ -1,26 +1,105 @@
 package repository
 
-import "github.com/codechamp1/certlens/internal/domains"
+import (
+	"context"
+
+	"github.com/codechamp1/certlens/internal/domains"
+)
 
 type mockRepository struct {
-	mockGetTLSSecrets func(namespace string) ([]domains.SecretInfo, error)
-	mockGetTLSSecret  func(namespace string, name string) (domains.SecretInfo, error)
+	mockGetTLSSecrets   func(ctx context.Context, namespace string) ([]domains.SecretInfo, error)
+	mockGetTLSSecret    func(ctx context.Context, namespace string, name string) (domains.SecretInfo, error)
+	mockWatchTLSSecrets func(ctx context.Context, namespace string) (<-chan domains.SecretEvent, error)
 }
 
 func NewMockRepository(
-	mockGetTLSSecrets func(namespace string) ([]domains.SecretInfo, error),
-	mockGetTLSSecret func(namespace, name string) (domains.SecretInfo, error),
+	mockGetTLSSecrets func(ctx context.Context, namespace string) ([]domains.SecretInfo, error),
+	mockGetTLSSecret func(ctx context.Context, namespace, name string) (domains.SecretInfo, error),
+	mockWatchTLSSecrets func(ctx context.Context, namespace string) (<-chan domains.SecretEvent, error),
 ) SecretsRepository {
 	return mockRepository{
-		mockGetTLSSecrets: mockGetTLSSecrets,
-		mockGetTLSSecret:  mockGetTLSSecret,
+		mockGetTLSSecrets:   mockGetTLSSecrets,
+		mockGetTLSSecret:    mockGetTLSSecret,
+		mockWatchTLSSecrets: mockWatchTLSSecrets,
+	}
+}
+
+func (m mockRepository) GetTLSSecrets(ctx context.Context, namespace string) ([]domains.SecretInfo, error) {
+	return m.mockGetTLSSecrets(ctx, namespace)
+}
+
+// GetTLSSecretsPages returns the secrets of the GetTLSSecrets mock as a single page.
+func (m mockRepository) GetTLSSecretsPages(ctx context.Context, namespace string, page func([]domains.SecretInfo) error) error {
+	secrets, err := m.mockGetTLSSecrets(ctx, namespace)
+	return singlePage(secrets, err, page)
+}
+
+func (m mockRepository) GetTLSSecret(ctx context.Context, namespace, name string) (domains.SecretInfo, error) {
+	return m.mockGetTLSSecret(ctx, namespace, name)
+}
+
+func (m mockRepository) WatchTLSSecrets(ctx context.Context, namespace string) (<-chan domains.SecretEvent, error) {
+	return m.mockWatchTLSSecrets(ctx, namespace)
+}
+
+type mockCertManagerRepository struct {
+	mockGetCertificates        func(ctx context.Context, namespace string) ([]domains.CertManagerCertificate, error)
+	mockGetCertificateRequests func(ctx context.Context, namespace string) ([]domains.CertManagerCertificateRequest, error)
+	mockGetIssuers             func(ctx context.Context, namespace string) ([]domains.CertManagerIssuer, error)
+}
+
+func NewMockCertManagerRepository(
+	mockGetCertificates func(ctx context.Context, namespace string) ([]domains.CertManagerCertificate, error),
+	mockGetCertificateRequests func(ctx context.Context, namespace string) ([]domains.CertManagerCertificateRequest, error),
+	mockGetIssuers func(ctx context.Context, namespace string) ([]domains.CertManagerIssuer, error),
+) CertManagerRepository {
+	return mockCertManagerRepository{
+		mockGetCertificates:        mockGetCertificates,
+		mockGetCertificateRequests: mockGetCertificateRequests,
+		mockGetIssuers:             mockGetIssuers,
+	}
+}
+
+func (m mockCertManagerRepository) GetCertificates(ctx context.Context, namespace string) ([]domains.CertManagerCertificate, error) {
+	return m.mockGetCertificates(ctx, namespace)
+}
+
+func (m mockCertManagerRepository) GetCertificateRequests(ctx context.Context, namespace string) ([]domains.CertManagerCertificateRequest, error) {
+	return m.mockGetCertificateRequests(ctx, namespace)
+}
+
+func (m mockCertManagerRepository) GetIssuers(ctx context.Context, namespace string) ([]domains.CertManagerIssuer, error) {
+	return m.mockGetIssuers(ctx, namespace)
+}
+
+type mockReferencesRepository struct {
+	mockGetSecretReferences func(ctx context.Context, namespace string) ([]domains.SecretReference, error)
+}
+
+func NewMockReferencesRepository(
+	mockGetSecretReferences func(ctx context.Context, namespace string) ([]domains.SecretReference, error),
+) ReferencesRepository {
+	return mockReferencesRepository{
+		mockGetSecretReferences: mockGetSecretReferences,
 	}
 }
 
-func (m mockRepository) GetTLSSecrets(namespace string) ([]domains.SecretInfo, error) {
-	return m.mockGetTLSSecrets(namespace)
+func (m mockReferencesRepository) GetSecretReferences(ctx context.Context, namespace string) ([]domains.SecretReference, error) {
+	return m.mockGetSecretReferences(ctx, namespace)
+}
+
+type mockProbeRepository struct {
+	mockProbeEndpoint func(ctx context.Context, namespace, target string) ([]byte, error)
+}
+
+func NewMockProbeRepository(
+	mockProbeEndpoint func(ctx context.Context, namespace, target string) ([]byte, error),
+) ProbeRepository {
+	return mockProbeRepository{
+		mockProbeEndpoint: mockProbeEndpoint,
+	}
 }
 
-func (m mockRepository) GetTLSSecret(namespace, name string) (domains.SecretInfo, error) {
-	return m.mockGetTLSSecret(namespace, name)
+func (m mockProbeRepository) ProbeEndpoint(ctx context.Context, namespace, target string) ([]byte, error) {
+	return m.mockProbeEndpoint(ctx, namespace, target)
 }
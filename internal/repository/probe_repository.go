@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/codechamp1/certlens/internal/client"
+)
+
+const serviceTargetPrefix = "service/"
+
+// ProbeRepository captures the certificate chain served by a live TLS endpoint.
+type ProbeRepository interface {
+	// ProbeEndpoint dials target and returns the presented chain PEM encoded. The target is
+	// host:port, or service/name:port for a Service in the namespace, which is reached through a
+	// port-forward and probed with name.namespace.svc as server name. Services require a namespace.
+	ProbeEndpoint(ctx context.Context, namespace, target string) ([]byte, error)
+}
+
+type probeRepository struct {
+	client client.TLSProber
+}
+
+func NewProbeRepository(client client.TLSProber) ProbeRepository {
+	return probeRepository{
+		client: client,
+	}
+}
+
+func (p probeRepository) ProbeEndpoint(ctx context.Context, namespace, target string) ([]byte, error) {
+	address, isService := strings.CutPrefix(target, serviceTargetPrefix)
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid probe target %s, expected host:port or service/name:port: %w", target, err)
+	}
+
+	var chain []*x509.Certificate
+	if isService {
+		if namespace == "" {
+			return nil, fmt.Errorf("probe target %s requires a namespace", target)
+		}
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port of probe target %s: %w", target, err)
+		}
+		if chain, err = p.client.ProbeServiceTLS(ctx, namespace, host, port, host+"."+namespace+".svc"); err != nil {
+			return nil, fmt.Errorf("failed to probe %s in namespace %s: %w", target, namespace, err)
+		}
+	} else if chain, err = p.client.ProbeTLS(ctx, address, host); err != nil {
+		return nil, fmt.Errorf("failed to probe %s: %w", target, err)
+	}
+
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("%s did not present a certificate", target)
+	}
+
+	var buf bytes.Buffer
+	for _, cert := range chain {
+		_ = pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,74 @@
+package repository_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	"github.com/codechamp1/certlens/internal/client"
+	"github.com/codechamp1/certlens/internal/repository"
+)
+
+func TestProbeEndpoint(t *testing.T) {
+	fixture := readFixture(t, "tls.crt")
+	block, _ := pem.Decode(fixture)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dialed []string
+	prober := client.NewMockTLSProber(
+		func(ctx context.Context, address, serverName string) ([]*x509.Certificate, error) {
+			dialed = append(dialed, address+" "+serverName)
+			if address == "down.example.com:443" {
+				return nil, errTest
+			}
+			return []*x509.Certificate{cert}, nil
+		},
+		func(ctx context.Context, namespace, service string, port int, serverName string) ([]*x509.Certificate, error) {
+			dialed = append(dialed, namespace+"/"+service+" "+serverName)
+			return []*x509.Certificate{cert}, nil
+		},
+	)
+
+	tests := []struct {
+		name           string
+		namespace      string
+		target         string
+		expectedDialed string
+		expectedErr    bool
+	}{
+		{name: "Should dial host:port with the host as server name", namespace: "default", target: "example.com:443", expectedDialed: "example.com:443 example.com"},
+		{name: "Should port-forward to services of the namespace", namespace: "default", target: "service/web:443", expectedDialed: "default/web web.default.svc"},
+		{name: "Should return error for services without namespace", target: "service/web:443", expectedErr: true},
+		{name: "Should return error for targets without port", namespace: "default", target: "example.com", expectedErr: true},
+		{name: "Should return error if the endpoint can not be probed", namespace: "default", target: "down.example.com:443", expectedDialed: "down.example.com:443 down.example.com", expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialed = nil
+			data, err := repository.NewProbeRepository(prober).ProbeEndpoint(context.Background(), tt.namespace, tt.target)
+
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("expected error: %v, got %v", tt.expectedErr, err)
+			}
+			if tt.target == "down.example.com:443" && !errors.Is(err, errTest) {
+				t.Errorf("expected error %v, got %v", errTest, err)
+			}
+			if tt.expectedDialed == "" && len(dialed) > 0 {
+				t.Errorf("expected no dial, got %v", dialed)
+			}
+			if tt.expectedDialed != "" && (len(dialed) != 1 || dialed[0] != tt.expectedDialed) {
+				t.Errorf("expected to dial %q, got %v", tt.expectedDialed, dialed)
+			}
+			if !tt.expectedErr && !bytes.Equal(data, pem.EncodeToMemory(block)) {
+				t.Errorf("expected the served chain PEM encoded, got %s", data)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/codechamp1/certlens/internal/client"
+	"github.com/codechamp1/certlens/internal/domains"
+)
+
+var (
+	ingressesResource = schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}
+	gatewaysResource  = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "gateways"}
+	routesResource    = schema.GroupVersionResource{Group: "route.openshift.io", Version: "v1", Resource: "routes"}
+)
+
+// ReferencesRepository finds the resources that serve TLS secrets. Gateway API and OpenShift
+// Routes are optional, clusters without them yield no references.
+type ReferencesRepository interface {
+	GetSecretReferences(ctx context.Context, namespace string) ([]domains.SecretReference, error)
+}
+
+type referencesRepository struct {
+	client client.ResourceFetcher
+}
+
+func NewReferencesRepository(client client.ResourceFetcher) ReferencesRepository {
+	return referencesRepository{
+		client: client,
+	}
+}
+
+// GetSecretReferences returns the references to the secrets of namespace, all namespaces if it
+// is empty. Gateways may reference secrets of other namespaces, so they are listed in all
+// namespaces and only in namespace itself if that is forbidden.
+func (r referencesRepository) GetSecretReferences(ctx context.Context, namespace string) ([]domains.SecretReference, error) {
+	mappers := []struct {
+		resource       schema.GroupVersionResource
+		mapper         func(unstructured.Unstructured) []domains.SecretReference
+		crossNamespace bool
+	}{
+		{ingressesResource, ingressReferences, false},
+		{gatewaysResource, gatewayReferences, true},
+		{routesResource, routeReferences, false},
+	}
+
+	var references []domains.SecretReference
+	for _, m := range mappers {
+		var resources []unstructured.Unstructured
+		var err error
+		if m.crossNamespace && namespace != "" {
+			resources, err = listResources(ctx, r.client, m.resource, "")
+			if apierrors.IsForbidden(err) {
+				resources, err = listResources(ctx, r.client, m.resource, namespace)
+			}
+		} else {
+			resources, err = listResources(ctx, r.client, m.resource, namespace)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, resource := range resources {
+			for _, reference := range m.mapper(resource) {
+				if namespace == "" || reference.Secret.Namespace == namespace {
+					references = append(references, reference)
+				}
+			}
+		}
+	}
+	return references, nil
+}
+
+// ingressReferences maps spec.tls[].secretName, secrets are always in the namespace of the Ingress.
+func ingressReferences(ingress unstructured.Unstructured) []domains.SecretReference {
+	entries, _, _ := unstructured.NestedSlice(ingress.Object, "spec", "tls")
+
+	var references []domains.SecretReference
+	for _, entry := range entries {
+		tls, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		secretName, _, _ := unstructured.NestedString(tls, "secretName")
+		if secretName == "" {
+			continue
+		}
+		hosts, _, _ := unstructured.NestedStringSlice(tls, "hosts")
+		references = append(references, newSecretReference("Ingress", ingress, secretName, "", hosts))
+	}
+	return references
+}
+
+// gatewayReferences maps spec.listeners[].tls.certificateRefs[] of kind Secret, which may point to
+// other namespaces.
+func gatewayReferences(gateway unstructured.Unstructured) []domains.SecretReference {
+	listeners, _, _ := unstructured.NestedSlice(gateway.Object, "spec", "listeners")
+
+	var references []domains.SecretReference
+	for _, entry := range listeners {
+		listener, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var hosts []string
+		if hostname, _, _ := unstructured.NestedString(listener, "hostname"); hostname != "" {
+			hosts = append(hosts, hostname)
+		}
+
+		refs, _, _ := unstructured.NestedSlice(listener, "tls", "certificateRefs")
+		for _, refEntry := range refs {
+			ref, ok := refEntry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			kind, _, _ := unstructured.NestedString(ref, "kind")
+			group, _, _ := unstructured.NestedString(ref, "group")
+			if (kind != "" && kind != "Secret") || group != "" {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(ref, "name")
+			namespace, _, _ := unstructured.NestedString(ref, "namespace")
+			references = append(references, newSecretReference("Gateway", gateway, name, namespace, hosts))
+		}
+	}
+	return references
+}
+
+// routeReferences maps spec.tls.externalCertificate.name, Routes with inline certificates do not
+// reference a secret.
+func routeReferences(route unstructured.Unstructured) []domains.SecretReference {
+	secretName, _, _ := unstructured.NestedString(route.Object, "spec", "tls", "externalCertificate", "name")
+	if secretName == "" {
+		return nil
+	}
+
+	var hosts []string
+	if host, _, _ := unstructured.NestedString(route.Object, "spec", "host"); host != "" {
+		hosts = append(hosts, host)
+	}
+	return []domains.SecretReference{newSecretReference("Route", route, secretName, "", hosts)}
+}
+
+func newSecretReference(kind string, resource unstructured.Unstructured, secretName, secretNamespace string, hosts []string) domains.SecretReference {
+	if secretNamespace == "" {
+		secretNamespace = resource.GetNamespace()
+	}
+	return domains.SecretReference{
+		K8SResourceID: domains.K8SResourceID{Name: resource.GetName(), Namespace: resource.GetNamespace()},
+		Kind:          kind,
+		Secret:        domains.K8SResourceID{Name: secretName, Namespace: secretNamespace},
+		Hosts:         hosts,
+	}
+}
@@ -0,0 +1,139 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/codechamp1/certlens/internal/client"
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/repository"
+)
+
+func TestGetSecretReferences(t *testing.T) {
+	resources := map[string]map[string]interface{}{
+		"ingresses": {
+			"metadata": map[string]interface{}{"name": "web", "namespace": "default"},
+			"spec": map[string]interface{}{
+				"tls": []interface{}{
+					map[string]interface{}{"secretName": "web-tls", "hosts": []interface{}{"example.com"}},
+					map[string]interface{}{"hosts": []interface{}{"no-secret.example.com"}},
+				},
+			},
+		},
+		"gateways": {
+			"metadata": map[string]interface{}{"name": "gateway", "namespace": "infra"},
+			"spec": map[string]interface{}{
+				"listeners": []interface{}{
+					map[string]interface{}{
+						"name":     "https",
+						"hostname": "*.example.com",
+						"tls": map[string]interface{}{
+							"certificateRefs": []interface{}{
+								map[string]interface{}{"name": "wildcard-tls", "namespace": "default"},
+								map[string]interface{}{"name": "local-tls", "kind": "Secret"},
+								map[string]interface{}{"name": "other", "kind": "ConfigMap"},
+							},
+						},
+					},
+				},
+			},
+		},
+		"routes": {
+			"metadata": map[string]interface{}{"name": "route", "namespace": "default"},
+			"spec": map[string]interface{}{
+				"host": "route.example.com",
+				"tls":  map[string]interface{}{"externalCertificate": map[string]interface{}{"name": "route-tls"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name               string
+		namespace          string
+		missingResources   map[string]bool
+		forbidClusterWide  bool
+		fetchErr           error
+		expectedReferences []domains.SecretReference
+		expectedErr        bool
+	}{
+		{
+			name: "Should map Ingress, Gateway and Route references",
+			expectedReferences: []domains.SecretReference{
+				{K8SResourceID: domains.K8SResourceID{Name: "web", Namespace: "default"}, Kind: "Ingress", Secret: domains.K8SResourceID{Name: "web-tls", Namespace: "default"}, Hosts: []string{"example.com"}},
+				{K8SResourceID: domains.K8SResourceID{Name: "gateway", Namespace: "infra"}, Kind: "Gateway", Secret: domains.K8SResourceID{Name: "wildcard-tls", Namespace: "default"}, Hosts: []string{"*.example.com"}},
+				{K8SResourceID: domains.K8SResourceID{Name: "gateway", Namespace: "infra"}, Kind: "Gateway", Secret: domains.K8SResourceID{Name: "local-tls", Namespace: "infra"}, Hosts: []string{"*.example.com"}},
+				{K8SResourceID: domains.K8SResourceID{Name: "route", Namespace: "default"}, Kind: "Route", Secret: domains.K8SResourceID{Name: "route-tls", Namespace: "default"}, Hosts: []string{"route.example.com"}},
+			},
+		},
+		{
+			name:             "Should skip APIs that are not installed",
+			missingResources: map[string]bool{"gateways": true, "routes": true},
+			expectedReferences: []domains.SecretReference{
+				{K8SResourceID: domains.K8SResourceID{Name: "web", Namespace: "default"}, Kind: "Ingress", Secret: domains.K8SResourceID{Name: "web-tls", Namespace: "default"}, Hosts: []string{"example.com"}},
+			},
+		},
+		{
+			name:      "Should list Gateways of all namespaces for the secrets of a namespace",
+			namespace: "default",
+			expectedReferences: []domains.SecretReference{
+				{K8SResourceID: domains.K8SResourceID{Name: "web", Namespace: "default"}, Kind: "Ingress", Secret: domains.K8SResourceID{Name: "web-tls", Namespace: "default"}, Hosts: []string{"example.com"}},
+				{K8SResourceID: domains.K8SResourceID{Name: "gateway", Namespace: "infra"}, Kind: "Gateway", Secret: domains.K8SResourceID{Name: "wildcard-tls", Namespace: "default"}, Hosts: []string{"*.example.com"}},
+				{K8SResourceID: domains.K8SResourceID{Name: "route", Namespace: "default"}, Kind: "Route", Secret: domains.K8SResourceID{Name: "route-tls", Namespace: "default"}, Hosts: []string{"route.example.com"}},
+			},
+		},
+		{
+			name:              "Should fall back to the Gateways of the namespace without cluster-wide access",
+			namespace:         "default",
+			forbidClusterWide: true,
+			expectedReferences: []domains.SecretReference{
+				{K8SResourceID: domains.K8SResourceID{Name: "web", Namespace: "default"}, Kind: "Ingress", Secret: domains.K8SResourceID{Name: "web-tls", Namespace: "default"}, Hosts: []string{"example.com"}},
+				{K8SResourceID: domains.K8SResourceID{Name: "route", Namespace: "default"}, Kind: "Route", Secret: domains.K8SResourceID{Name: "route-tls", Namespace: "default"}, Hosts: []string{"route.example.com"}},
+			},
+		},
+		{
+			name:        "Should return error if the resources can not be listed",
+			fetchErr:    errTest,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := client.NewMockResourceFetcher(func(ctx context.Context, resource schema.GroupVersionResource, namespace string) (*unstructured.UnstructuredList, error) {
+				if tt.fetchErr != nil {
+					return nil, tt.fetchErr
+				}
+				if tt.missingResources[resource.Resource] {
+					return nil, apierrors.NewNotFound(resource.GroupResource(), "")
+				}
+				if tt.forbidClusterWide && namespace == "" {
+					return nil, apierrors.NewForbidden(resource.GroupResource(), "", errTest)
+				}
+				object := unstructured.Unstructured{Object: resources[resource.Resource]}
+				if namespace != "" && object.GetNamespace() != namespace {
+					return &unstructured.UnstructuredList{}, nil
+				}
+				return &unstructured.UnstructuredList{Items: []unstructured.Unstructured{object}}, nil
+			})
+
+			references, err := repository.NewReferencesRepository(mockClient).GetSecretReferences(context.Background(), tt.namespace)
+
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("expected error: %v, got %v", tt.expectedErr, err)
+			}
+			if tt.expectedErr && !errors.Is(err, errTest) {
+				t.Errorf("expected error %v, got %v", errTest, err)
+			}
+
+			if !reflect.DeepEqual(references, tt.expectedReferences) {
+				t.Errorf("expected references %+v, got %+v", tt.expectedReferences, references)
+			}
+		})
+	}
+}
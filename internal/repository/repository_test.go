@@ -1,12 +1,15 @@
 package repository_test
 
 import (
+	"context"
+	"encoding/pem"
 	"errors"
 	"reflect"
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 
 	"github.com/codechamp1/certlens/internal/client"
 	"github.com/codechamp1/certlens/internal/domains"
@@ -17,7 +20,7 @@ var errTest = errors.New("simulated error")
 
 func TestNewSecretsRepository(t *testing.T) {
 	t.Run("Should create a repository with the given client", func(t *testing.T) {
-		mockClient := client.NewMockSecretsFetcher(nil, nil)
+		mockClient := client.NewMockSecretsFetcher(nil, nil, nil)
 		repo := repository.NewSecretsRepository(mockClient)
 		if repo == nil {
 			t.Error("Expected repository to be created, but got nil")
@@ -100,15 +103,16 @@ func TestGetTLSSecrets(t *testing.T) {
 	for _, tt := range test {
 		t.Run(tt.name, func(t *testing.T) {
 			mockClient := client.NewMockSecretsFetcher(
-				func(namespace string) (*v1.SecretList, error) {
+				func(ctx context.Context, namespace string) (*v1.SecretList, error) {
 					return &tt.secrets, tt.expectedErr
 				},
 				nil,
+				nil,
 			)
 
 			repo := repository.NewSecretsRepository(mockClient)
 
-			secrets, err := repo.GetTLSSecrets(tt.namespace)
+			secrets, err := repo.GetTLSSecrets(context.Background(), tt.namespace)
 			if !errors.Is(err, tt.expectedErr) {
 				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
 			}
@@ -125,7 +129,65 @@ func TestGetTLSSecrets(t *testing.T) {
 	}
 }
 
+// pagedFetcher returns its pages from FetchTLSSecretsPages.
+type pagedFetcher struct {
+	client.SecretsFetcher
+	pages []v1.SecretList
+}
+
+func (p pagedFetcher) FetchTLSSecretsPages(ctx context.Context, namespace string, page func(*v1.SecretList) error) error {
+	for _, secrets := range p.pages {
+		if err := page(&secrets); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestGetTLSSecretsPages(t *testing.T) {
+	tlsSecret := func(name string) v1.Secret {
+		return v1.Secret{Type: v1.SecretTypeTLS, ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	}
+	fetcher := pagedFetcher{pages: []v1.SecretList{
+		{Items: []v1.Secret{tlsSecret("first"), {ObjectMeta: metav1.ObjectMeta{Name: "opaque", Namespace: "default"}}}},
+		{Items: []v1.Secret{tlsSecret("second"), tlsSecret("third")}},
+	}}
+	repo := repository.NewSecretsRepository(fetcher)
+
+	t.Run("Should map every page of TLS secrets", func(t *testing.T) {
+		var sizes []int
+		err := repo.GetTLSSecretsPages(context.Background(), "default", func(secrets []domains.SecretInfo) error {
+			sizes = append(sizes, len(secrets))
+			return nil
+		})
+		if err != nil || !reflect.DeepEqual(sizes, []int{1, 2}) {
+			t.Errorf("expected pages of 1 and 2 TLS secrets, got %v, %v", sizes, err)
+		}
+	})
+
+	t.Run("Should stop at the first page error", func(t *testing.T) {
+		calls := 0
+		err := repo.GetTLSSecretsPages(context.Background(), "default", func(secrets []domains.SecretInfo) error {
+			calls++
+			return errTest
+		})
+		if !errors.Is(err, errTest) || calls != 1 {
+			t.Errorf("expected error %v after one page, got %v after %d", errTest, err, calls)
+		}
+	})
+
+	t.Run("Should collect all pages", func(t *testing.T) {
+		secrets, err := repo.GetTLSSecrets(context.Background(), "default")
+		if err != nil || len(secrets) != 3 {
+			t.Errorf("expected 3 secrets, got %+v, %v", secrets, err)
+		}
+	})
+}
+
 func TestGetTLSSecret(t *testing.T) {
+	block, _ := pem.Decode(readFixture(t, "tls.crt"))
+	certPEM := pem.EncodeToMemory(block)
+
 	test := []struct {
 		name        string
 		namespace   string
@@ -152,6 +214,7 @@ func TestGetTLSSecret(t *testing.T) {
 				Data: map[string][]byte{
 					v1.TLSCertKey:       []byte("cert-data"),
 					v1.TLSPrivateKeyKey: []byte("key-data"),
+					"ca.crt":            []byte("ca-data"),
 				},
 			},
 			expected: domains.SecretInfo{
@@ -160,6 +223,40 @@ func TestGetTLSSecret(t *testing.T) {
 				Type:      "kubernetes.io/tls",
 				TLSCert:   []byte("cert-data"),
 				TLSKey:    []byte("key-data"),
+				CACert:    []byte("ca-data"),
+			},
+		},
+		{
+			name:      "Should keep other keys holding PEM, DER or JKS certificates as PEM",
+			namespace: "default",
+			secret: v1.Secret{
+				Type: v1.SecretTypeTLS,
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tls-secret-2",
+					Namespace: "default",
+				},
+				Data: map[string][]byte{
+					v1.TLSCertKey:     []byte("cert-data"),
+					domains.CACertKey: block.Bytes,
+					"truststore.pem":  certPEM,
+					"truststore.der":  block.Bytes,
+					"truststore.jks":  encodeJKS(block.Bytes),
+					"keystore.p12":    encodePKCS12(t),
+					"password":        []byte("not a certificate"),
+				},
+			},
+			expected: domains.SecretInfo{
+				Name:      "tls-secret-2",
+				Namespace: "default",
+				Type:      "kubernetes.io/tls",
+				TLSCert:   []byte("cert-data"),
+				CACert:    certPEM,
+				PEMData: map[string][]byte{
+					"truststore.pem": certPEM,
+					"truststore.der": certPEM,
+					"truststore.jks": certPEM,
+				},
+				Unsupported: map[string]string{"keystore.p12": "PKCS#12 keystores are password protected and can not be inspected"},
 			},
 		},
 		{
@@ -180,14 +277,15 @@ func TestGetTLSSecret(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockClient := client.NewMockSecretsFetcher(
 				nil,
-				func(namespace, name string) (*v1.Secret, error) {
+				func(ctx context.Context, namespace, name string) (*v1.Secret, error) {
 					return &tt.secret, tt.expectedErr
 				},
+				nil,
 			)
 
 			repo := repository.NewSecretsRepository(mockClient)
 
-			secret, err := repo.GetTLSSecret(tt.namespace, tt.secret.Name)
+			secret, err := repo.GetTLSSecret(context.Background(), tt.namespace, tt.secret.Name)
 
 			if !errors.Is(err, tt.expectedErr) {
 				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
@@ -199,3 +297,55 @@ func TestGetTLSSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestWatchTLSSecrets(t *testing.T) {
+	t.Run("Should return error if the client can not watch secrets", func(t *testing.T) {
+		mockClient := client.NewMockSecretsFetcher(nil, nil, func(ctx context.Context, namespace string) (<-chan client.SecretEvent, error) {
+			return nil, errTest
+		})
+
+		repo := repository.NewSecretsRepository(mockClient)
+		if _, err := repo.WatchTLSSecrets(context.Background(), "default"); !errors.Is(err, errTest) {
+			t.Errorf("Expected error %v, got %v", errTest, err)
+		}
+	})
+
+	t.Run("Should map TLS secret events and skip other secrets", func(t *testing.T) {
+		clientEvents := make(chan client.SecretEvent, 3)
+		clientEvents <- client.SecretEvent{Type: watch.Added, Secret: &v1.Secret{
+			Type:       v1.SecretTypeTLS,
+			ObjectMeta: metav1.ObjectMeta{Name: "tls-secret-1", Namespace: "default"},
+		}}
+		clientEvents <- client.SecretEvent{Type: watch.Added, Secret: &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "simple-secret-1", Namespace: "default"},
+		}}
+		clientEvents <- client.SecretEvent{Type: watch.Deleted, Secret: &v1.Secret{
+			Type:       v1.SecretTypeTLS,
+			ObjectMeta: metav1.ObjectMeta{Name: "tls-secret-1", Namespace: "default"},
+		}}
+		close(clientEvents)
+
+		mockClient := client.NewMockSecretsFetcher(nil, nil, func(ctx context.Context, namespace string) (<-chan client.SecretEvent, error) {
+			return clientEvents, nil
+		})
+
+		repo := repository.NewSecretsRepository(mockClient)
+		events, err := repo.WatchTLSSecrets(context.Background(), "default")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var got []domains.SecretEvent
+		for event := range events {
+			got = append(got, event)
+		}
+
+		expected := []domains.SecretEvent{
+			{Type: domains.SecretAdded, Secret: domains.SecretInfo{Name: "tls-secret-1", Namespace: "default", Type: "kubernetes.io/tls"}},
+			{Type: domains.SecretDeleted, Secret: domains.SecretInfo{Name: "tls-secret-1", Namespace: "default", Type: "kubernetes.io/tls"}},
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("Expected events %+v, got %+v", expected, got)
+		}
+	})
+}
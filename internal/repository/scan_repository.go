@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/codechamp1/certlens/internal/client"
+	"github.com/codechamp1/certlens/internal/domains"
+)
+
+// scanRepository serves the TLS secrets of a namespace together with the Opaque secrets and
+// ConfigMaps holding certificates, such as CA bundles, truststores and webhook certificates.
+// The first key holding a certificate, in sorted order, becomes TLSCert and the other keys
+// holding certificates become PEMData. Like for local files, tls.crt is paired with tls.key
+// and server.crt with server.key, unless the key holding the certificate also holds the key.
+// PKCS#12 keystores are recorded as Unsupported, resources holding neither are skipped.
+type scanRepository struct {
+	secrets    client.SecretsFetcher
+	configMaps client.ConfigMapsFetcher
+	tls        SecretsRepository
+}
+
+func NewScanRepository(secrets client.SecretsFetcher, configMaps client.ConfigMapsFetcher) SecretsRepository {
+	return scanRepository{
+		secrets:    secrets,
+		configMaps: configMaps,
+		tls:        NewSecretsRepository(secrets),
+	}
+}
+
+func (s scanRepository) GetTLSSecrets(ctx context.Context, namespace string) ([]domains.SecretInfo, error) {
+	var found []domains.SecretInfo
+	err := s.GetTLSSecretsPages(ctx, namespace, func(page []domains.SecretInfo) error {
+		found = append(found, page...)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// GetTLSSecretsPages scans the secrets and then the ConfigMaps of the namespace page by page as
+// the API server returns them, every page holds the findings of one chunk.
+func (s scanRepository) GetTLSSecretsPages(ctx context.Context, namespace string, page func([]domains.SecretInfo) error) error {
+	err := s.secrets.FetchSecretsPages(ctx, namespace, func(secrets *corev1.SecretList) error {
+		var found []domains.SecretInfo
+		for _, secret := range secrets.Items {
+			if secret.Type == corev1.SecretTypeTLS {
+				found = append(found, mapSecretToModel(secret))
+			} else if model, ok := scanSecret(secret); ok {
+				found = append(found, model)
+			}
+		}
+		return page(found)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get secrets in namespace %s: %w", namespace, err)
+	}
+
+	err = s.configMaps.FetchConfigMapsPages(ctx, namespace, func(configMaps *corev1.ConfigMapList) error {
+		var found []domains.SecretInfo
+		for _, configMap := range configMaps.Items {
+			if model, ok := scanConfigMap(configMap); ok {
+				found = append(found, model)
+			}
+		}
+		return page(found)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get configmaps in namespace %s: %w", namespace, err)
+	}
+
+	return nil
+}
+
+// GetTLSSecret returns a TLS secret by name, or a scanned resource by its domains.K8SResourceID.Ref.
+func (s scanRepository) GetTLSSecret(ctx context.Context, namespace, ref string) (domains.SecretInfo, error) {
+	kind, name := domains.ParseRef(ref)
+
+	var (
+		model domains.SecretInfo
+		ok    bool
+	)
+	switch kind {
+	case "":
+		return s.tls.GetTLSSecret(ctx, namespace, name)
+	case domains.KindSecret:
+		secret, err := s.secrets.FetchSecret(ctx, namespace, name)
+		if err != nil {
+			return domains.SecretInfo{}, fmt.Errorf("failed to get secret %s in namespace %s: %w", name, namespace, err)
+		}
+		model, ok = scanSecret(*secret)
+	case domains.KindConfigMap:
+		configMap, err := s.configMaps.FetchConfigMap(ctx, namespace, name)
+		if err != nil {
+			return domains.SecretInfo{}, fmt.Errorf("failed to get configmap %s in namespace %s: %w", name, namespace, err)
+		}
+		model, ok = scanConfigMap(*configMap)
+	default:
+		return domains.SecretInfo{}, fmt.Errorf("can not scan resources of kind %s", kind)
+	}
+
+	if !ok {
+		return domains.SecretInfo{}, fmt.Errorf("%s %s in namespace %s does not contain certificates", kind, name, namespace)
+	}
+	return model, nil
+}
+
+// WatchTLSSecrets only watches TLS secrets, changes of scanned resources show up on the next listing.
+func (s scanRepository) WatchTLSSecrets(ctx context.Context, namespace string) (<-chan domains.SecretEvent, error) {
+	return s.tls.WatchTLSSecrets(ctx, namespace)
+}
+
+func scanSecret(secret corev1.Secret) (domains.SecretInfo, bool) {
+	return scanData(domains.KindSecret, secret.ObjectMeta, string(secret.Type), secret.Data)
+}
+
+func scanConfigMap(configMap corev1.ConfigMap) (domains.SecretInfo, bool) {
+	data := make(map[string][]byte, len(configMap.Data)+len(configMap.BinaryData))
+	for key, value := range configMap.Data {
+		data[key] = []byte(value)
+	}
+	for key, value := range configMap.BinaryData {
+		data[key] = value
+	}
+	return scanData(domains.KindConfigMap, configMap.ObjectMeta, "", data)
+}
+
+// scanData maps the keys of a resource holding PEM or DER certificates to a secret model. It
+// reports false if no key holds a certificate or an unsupported keystore.
+func scanData(kind string, meta metav1.ObjectMeta, secretType string, data map[string][]byte) (domains.SecretInfo, bool) {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	model := domains.SecretInfo{
+		Name:      meta.Name,
+		Namespace: meta.Namespace,
+		Kind:      kind,
+		Type:      secretType,
+		Labels:    meta.Labels,
+	}
+	for _, key := range keys {
+		certs, privateKeys := sniffCertificates(data[key])
+		if len(certs) == 0 && isPKCS12(data[key]) {
+			if model.Unsupported == nil {
+				model.Unsupported = map[string]string{}
+			}
+			model.Unsupported[key] = pkcs12Unsupported
+			continue
+		}
+		if len(certs) == 0 {
+			continue
+		}
+		if model.CertKey == "" {
+			model.CertKey = key
+			model.TLSCert = certs
+			model.TLSKey = privateKeys
+			if len(privateKeys) == 0 {
+				model.TLSKey = data[strings.TrimSuffix(key, path.Ext(key))+".key"]
+			}
+			continue
+		}
+		if model.PEMData == nil {
+			model.PEMData = map[string][]byte{}
+		}
+		model.PEMData[key] = certs
+	}
+
+	return model, model.CertKey != "" || len(model.Unsupported) > 0
+}
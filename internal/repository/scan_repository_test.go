@@ -0,0 +1,184 @@
+package repository_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/codechamp1/certlens/internal/client"
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/repository"
+)
+
+func TestScanRepository(t *testing.T) {
+	cert, key := readFixture(t, "tls.crt"), readFixture(t, "tls.key")
+	block, _ := pem.Decode(cert)
+
+	secrets := []v1.Secret{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "default"},
+			Type:       v1.SecretTypeTLS,
+			Data:       map[string][]byte{"tls.crt": cert, "tls.key": key},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "webhook", Namespace: "default"},
+			Type:       v1.SecretTypeOpaque,
+			Data:       map[string][]byte{"tls.crt": cert, "tls.key": key, "truststore.pem": cert, "password": []byte("secret")},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "keystores", Namespace: "default"},
+			Type:       v1.SecretTypeOpaque,
+			Data:       map[string][]byte{"truststore.jks": encodeJKS(block.Bytes), "keystore.p12": encodePKCS12(t)},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "default"},
+			Type:       v1.SecretTypeOpaque,
+			Data:       map[string][]byte{"password": []byte("secret"), "keystore.p12": encodePKCS12(t)},
+		},
+	}
+	configMaps := []v1.ConfigMap{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-root-ca.crt", Namespace: "default"},
+			Data:       map[string]string{"ca.crt": string(cert)},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "binary", Namespace: "default"},
+			BinaryData: map[string][]byte{"cert.der": block.Bytes},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"},
+			Data:       map[string]string{"config.yaml": "debug: true"},
+		},
+	}
+
+	secretsClient := client.NewMockSecretsFetcher(
+		func(ctx context.Context, namespace string) (*v1.SecretList, error) {
+			return &v1.SecretList{Items: secrets}, nil
+		},
+		func(ctx context.Context, namespace, name string) (*v1.Secret, error) {
+			for _, secret := range secrets {
+				if secret.Name == name {
+					return &secret, nil
+				}
+			}
+			return nil, errTest
+		},
+		nil,
+	)
+	configMapsClient := client.NewMockConfigMapsFetcher(
+		func(ctx context.Context, namespace string) (*v1.ConfigMapList, error) {
+			return &v1.ConfigMapList{Items: configMaps}, nil
+		},
+		func(ctx context.Context, namespace, name string) (*v1.ConfigMap, error) {
+			for _, configMap := range configMaps {
+				if configMap.Name == name {
+					return &configMap, nil
+				}
+			}
+			return nil, errTest
+		},
+	)
+	repo := repository.NewScanRepository(secretsClient, configMapsClient)
+
+	t.Run("Should list TLS secrets and the secrets and configmaps holding certificates", func(t *testing.T) {
+		found, err := repo.GetTLSSecrets(context.Background(), "default")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		var refs []string
+		for _, secret := range found {
+			refs = append(refs, secret.ID().Ref())
+		}
+		expected := []string{"tls", "Secret/webhook", "Secret/keystores", "Secret/credentials", "ConfigMap/kube-root-ca.crt", "ConfigMap/binary"}
+		if len(refs) != len(expected) {
+			t.Fatalf("expected %v, got %v", expected, refs)
+		}
+		for i := range expected {
+			if refs[i] != expected[i] {
+				t.Errorf("expected %v, got %v", expected, refs)
+			}
+		}
+	})
+
+	t.Run("Should pair the certificate key with its private key and keep the other certificates", func(t *testing.T) {
+		secret, err := repo.GetTLSSecret(context.Background(), "default", "Secret/webhook")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if secret.Kind != domains.KindSecret || secret.CertKey != "tls.crt" || secret.Type != string(v1.SecretTypeOpaque) {
+			t.Errorf("unexpected secret %+v", secret)
+		}
+		if !bytes.Equal(secret.TLSKey, key) {
+			t.Error("expected tls.key to be paired with tls.crt")
+		}
+		if _, ok := secret.PEMData["truststore.pem"]; !ok || len(secret.PEMData) != 1 {
+			t.Errorf("expected only truststore.pem in the PEM data, got %v", secret.PEMData)
+		}
+	})
+
+	t.Run("Should convert DER certificates of binary data to PEM", func(t *testing.T) {
+		configMap, err := repo.GetTLSSecret(context.Background(), "default", "ConfigMap/binary")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if configMap.CertKey != "cert.der" || !bytes.Contains(configMap.TLSCert, []byte("-----BEGIN CERTIFICATE-----")) {
+			t.Errorf("unexpected configmap %+v", configMap)
+		}
+	})
+
+	t.Run("Should read Java KeyStores and record PKCS#12 keystores as unsupported", func(t *testing.T) {
+		secret, err := repo.GetTLSSecret(context.Background(), "default", "Secret/keystores")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if secret.CertKey != "truststore.jks" || !bytes.Contains(secret.TLSCert, []byte("-----BEGIN CERTIFICATE-----")) {
+			t.Errorf("expected the certificate of truststore.jks, got %+v", secret)
+		}
+		if len(secret.Unsupported) != 1 || !strings.Contains(secret.Unsupported["keystore.p12"], "PKCS#12") {
+			t.Errorf("expected keystore.p12 to be unsupported, got %v", secret.Unsupported)
+		}
+	})
+
+	t.Run("Should keep resources holding only PKCS#12 keystores with the unsupported reason", func(t *testing.T) {
+		secret, err := repo.GetTLSSecret(context.Background(), "default", "Secret/credentials")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if secret.CertKey != "" || len(secret.TLSCert) != 0 || secret.Unsupported["keystore.p12"] == "" {
+			t.Errorf("expected only keystore.p12 as unsupported, got %+v", secret)
+		}
+	})
+
+	t.Run("Should get TLS secrets by name", func(t *testing.T) {
+		secret, err := repo.GetTLSSecret(context.Background(), "default", "tls")
+		if err != nil || secret.Kind != "" || secret.Name != "tls" {
+			t.Errorf("expected the TLS secret, got %+v, %v", secret, err)
+		}
+	})
+
+	t.Run("Should return error for resources without certificates", func(t *testing.T) {
+		if _, err := repo.GetTLSSecret(context.Background(), "default", "ConfigMap/settings"); err == nil {
+			t.Error("expected error, got nil")
+		}
+		if _, err := repo.GetTLSSecret(context.Background(), "default", "Pod/web"); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("Should return error if the configmaps can not be listed", func(t *testing.T) {
+		failing := client.NewMockConfigMapsFetcher(func(ctx context.Context, namespace string) (*v1.ConfigMapList, error) {
+			return nil, errTest
+		}, nil)
+		_, err := repository.NewScanRepository(secretsClient, failing).GetTLSSecrets(context.Background(), "default")
+		if !errors.Is(err, errTest) {
+			t.Errorf("expected error %v, got %v", errTest, err)
+		}
+	})
+}
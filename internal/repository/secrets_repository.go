@@ -1,17 +1,24 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
 
 	"github.com/codechamp1/certlens/internal/client"
 	"github.com/codechamp1/certlens/internal/domains"
 )
 
 type SecretsRepository interface {
-	GetTLSSecrets(namespace string) ([]domains.SecretInfo, error)
-	GetTLSSecret(namespace, name string) (domains.SecretInfo, error)
+	GetTLSSecrets(ctx context.Context, namespace string) ([]domains.SecretInfo, error)
+	// GetTLSSecretsPages streams the secrets of GetTLSSecrets in chunks, it stops at the first
+	// error returned by page.
+	GetTLSSecretsPages(ctx context.Context, namespace string, page func([]domains.SecretInfo) error) error
+	GetTLSSecret(ctx context.Context, namespace, name string) (domains.SecretInfo, error)
+	WatchTLSSecrets(ctx context.Context, namespace string) (<-chan domains.SecretEvent, error)
 }
 
 type secretsRepository struct {
@@ -24,24 +31,51 @@ func NewSecretsRepository(client client.SecretsFetcher) SecretsRepository {
 	}
 }
 
-func (s secretsRepository) GetTLSSecrets(namespace string) ([]domains.SecretInfo, error) {
-	secretsList, err := s.client.FetchSecrets(namespace)
+func (s secretsRepository) GetTLSSecrets(ctx context.Context, namespace string) ([]domains.SecretInfo, error) {
+	var tlsSecrets []domains.SecretInfo
+	err := s.GetTLSSecretsPages(ctx, namespace, func(secrets []domains.SecretInfo) error {
+		tlsSecrets = append(tlsSecrets, secrets...)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get secrets in namespace %s: %w", namespace, err)
+		return nil, err
 	}
 
-	var tlsSecrets []domains.SecretInfo
-	for _, secret := range secretsList.Items {
-		if secret.Type == corev1.SecretTypeTLS {
-			tlsSecrets = append(tlsSecrets, mapSecretToModel(secret))
+	return tlsSecrets, nil
+}
+
+func (s secretsRepository) GetTLSSecretsPages(ctx context.Context, namespace string, page func([]domains.SecretInfo) error) error {
+	err := s.client.FetchTLSSecretsPages(ctx, namespace, func(secretsList *corev1.SecretList) error {
+		var tlsSecrets []domains.SecretInfo
+		for _, secret := range secretsList.Items {
+			if secret.Type == corev1.SecretTypeTLS {
+				tlsSecrets = append(tlsSecrets, mapSecretToModel(secret))
+			}
 		}
+		return page(tlsSecrets)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get secrets in namespace %s: %w", namespace, err)
 	}
 
-	return tlsSecrets, nil
+	return nil
 }
 
-func (s secretsRepository) GetTLSSecret(namespace, name string) (domains.SecretInfo, error) {
-	secret, err := s.client.FetchSecret(namespace, name)
+// singlePage streams the secrets of repositories that read everything at once as one page. The
+// secrets of a domains.PartialError are streamed before it is returned.
+func singlePage(secrets []domains.SecretInfo, err error, page func([]domains.SecretInfo) error) error {
+	var partial *domains.PartialError
+	if err != nil && !errors.As(err, &partial) {
+		return err
+	}
+	if pageErr := page(secrets); pageErr != nil {
+		return pageErr
+	}
+	return err
+}
+
+func (s secretsRepository) GetTLSSecret(ctx context.Context, namespace, name string) (domains.SecretInfo, error) {
+	secret, err := s.client.FetchSecret(ctx, namespace, name)
 
 	if err != nil {
 		return domains.SecretInfo{}, fmt.Errorf("failed to get secret %s in namespace %s: %w", name, namespace, err)
@@ -54,12 +88,86 @@ func (s secretsRepository) GetTLSSecret(namespace, name string) (domains.SecretI
 	return mapSecretToModel(*secret), nil
 }
 
+func (s secretsRepository) WatchTLSSecrets(ctx context.Context, namespace string) (<-chan domains.SecretEvent, error) {
+	secretEvents, err := s.client.WatchSecrets(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch secrets in namespace %s: %w", namespace, err)
+	}
+
+	events := make(chan domains.SecretEvent)
+	go func() {
+		defer close(events)
+		for event := range secretEvents {
+			if event.Secret.Type != corev1.SecretTypeTLS {
+				continue
+			}
+
+			eventType, ok := secretEventTypes[event.Type]
+			if !ok {
+				continue
+			}
+
+			select {
+			case events <- domains.SecretEvent{Type: eventType, Secret: mapSecretToModel(*event.Secret)}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+var secretEventTypes = map[watch.EventType]domains.SecretEventType{
+	watch.Added:    domains.SecretAdded,
+	watch.Modified: domains.SecretModified,
+	watch.Deleted:  domains.SecretDeleted,
+}
+
 func mapSecretToModel(secret corev1.Secret) domains.SecretInfo {
+	pemData, unsupported := otherCertificates(secret)
 	return domains.SecretInfo{
-		Name:      secret.Name,
-		Namespace: secret.Namespace,
-		Type:      string(secret.Type),
-		TLSCert:   secret.Data[corev1.TLSCertKey],
-		TLSKey:    secret.Data[corev1.TLSPrivateKeyKey],
+		Name:        secret.Name,
+		Namespace:   secret.Namespace,
+		Type:        string(secret.Type),
+		TLSCert:     secret.Data[corev1.TLSCertKey],
+		TLSKey:      secret.Data[corev1.TLSPrivateKeyKey],
+		CACert:      caCert(secret),
+		PEMData:     pemData,
+		Unsupported: unsupported,
+		Labels:      secret.Labels,
+	}
+}
+
+// caCert returns the certificates of ca.crt as PEM. Values that hold no certificate are kept
+// as they are, so the bundle reports why they can not be parsed.
+func caCert(secret corev1.Secret) []byte {
+	value := secret.Data[domains.CACertKey]
+	if certs, _ := sniffCertificates(value); len(certs) > 0 {
+		return certs
+	}
+	return value
+}
+
+// otherCertificates collects the keys besides tls.crt, tls.key and ca.crt that hold PEM, DER or
+// Java KeyStore certificates as PEM, and the PKCS#12 keystores that can not be read.
+func otherCertificates(secret corev1.Secret) (pemData map[string][]byte, unsupported map[string]string) {
+	for key, value := range secret.Data {
+		if key == corev1.TLSCertKey || key == corev1.TLSPrivateKeyKey || key == domains.CACertKey {
+			continue
+		}
+		certs, _ := sniffCertificates(value)
+		switch {
+		case len(certs) > 0:
+			if pemData == nil {
+				pemData = map[string][]byte{}
+			}
+			pemData[key] = certs
+		case isPKCS12(value):
+			if unsupported == nil {
+				unsupported = map[string]string{}
+			}
+			unsupported[key] = pkcs12Unsupported
+		}
 	}
+	return pemData, unsupported
 }
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/codechamp1/certlens/internal/domains"
+)
+
+// PEMBundle holds the certificates of a secret key other than tls.crt, such as ca.crt. Keys
+// that can not be read at all, such as PKCS#12 keystores, only carry the Error.
+type PEMBundle struct {
+	Key          string            `json:"key"`
+	Certificates []CertificateInfo `json:"certificates,omitempty"`
+	Error        string            `json:"error,omitempty"`
+}
+
+// parseBundles parses ca.crt followed by the other PEM keys of the secret in key order. Keys
+// that can not be parsed are kept with the error, they do not fail the inspection, and the
+// unsupported keys follow with their reason.
+func parseBundles(secret domains.SecretInfo, thresholds ExpiryThresholds) []PEMBundle {
+	keys := make([]string, 0, len(secret.PEMData))
+	for key := range secret.PEMData {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	unsupported := make([]string, 0, len(secret.Unsupported))
+	for key := range secret.Unsupported {
+		unsupported = append(unsupported, key)
+	}
+	sort.Strings(unsupported)
+
+	data := map[string][]byte{}
+	for key, value := range secret.PEMData {
+		data[key] = value
+	}
+	if len(secret.CACert) > 0 {
+		keys = append([]string{domains.CACertKey}, keys...)
+		data[domains.CACertKey] = secret.CACert
+	}
+
+	var bundles []PEMBundle
+	for _, key := range keys {
+		bundle := PEMBundle{Key: key}
+		certs, err := parseCertsFromString(string(data[key]))
+		if err != nil {
+			bundle.Error = err.Error()
+		} else {
+			bundle.Certificates = parseCertificates(certs, thresholds)
+		}
+		bundles = append(bundles, bundle)
+	}
+	for _, key := range unsupported {
+		bundles = append(bundles, PEMBundle{Key: key, Error: secret.Unsupported[key]})
+	}
+	return bundles
+}
+
+// unsupportedReasons lists the unsupported keys of a secret holding no readable certificate.
+func unsupportedReasons(secret domains.SecretInfo) string {
+	reasons := make([]string, 0, len(secret.Unsupported))
+	for key, reason := range secret.Unsupported {
+		reasons = append(reasons, key+": "+reason)
+	}
+	sort.Strings(reasons)
+	return strings.Join(reasons, ", ")
+}
@@ -0,0 +1,61 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/repository"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+func TestInspectTLSSecretBundles(t *testing.T) {
+	now := time.Now()
+	from, to := now.Add(-time.Hour), now.Add(24*time.Hour)
+
+	root := issueTestCertificate(t, "root", nil, true, from, to)
+	otherRoot := issueTestCertificate(t, "other-root", nil, true, from, to)
+	leaf := issueTestCertificate(t, "leaf", root, false, from, to)
+
+	repo := repository.NewMockRepository(nil, func(ctx context.Context, namespace, name string) (domains.SecretInfo, error) {
+		return domains.SecretInfo{
+			Name:      name,
+			Namespace: namespace,
+			TLSCert:   pemBundle(leaf),
+			CACert:    pemBundle(root),
+			PEMData: map[string][]byte{
+				"truststore.pem": pemBundle(otherRoot, root),
+				"broken.pem":     []byte("-----BEGIN CERTIFICATE-----\nbroken\n-----END CERTIFICATE-----\n"),
+			},
+		}, nil
+	}, nil)
+
+	inspection, err := service.NewSecretsService(repo).InspectTLSSecret(context.Background(), "default", "tls-secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		key          string
+		certificates int
+		failed       bool
+	}{
+		{key: "ca.crt", certificates: 1},
+		{key: "broken.pem", failed: true},
+		{key: "truststore.pem", certificates: 2},
+	}
+
+	if len(inspection.Bundles) != len(expected) {
+		t.Fatalf("expected %d bundles, got %+v", len(expected), inspection.Bundles)
+	}
+	for i, bundle := range inspection.Bundles {
+		if bundle.Key != expected[i].key || len(bundle.Certificates) != expected[i].certificates || (bundle.Error != "") != expected[i].failed {
+			t.Errorf("expected bundle %+v, got key %s with %d certificates and error %q", expected[i], bundle.Key, len(bundle.Certificates), bundle.Error)
+		}
+	}
+
+	if !inspection.Chain.Trusted {
+		t.Errorf("expected the chain to be trusted through ca.crt, got %+v", inspection.Chain)
+	}
+}
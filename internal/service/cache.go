@@ -0,0 +1,44 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// lookupTTL is how long the cert-manager resources and secret references of a namespace are
+// reused, selecting secrets one after another in the TUI would otherwise list them every time.
+const lookupTTL = 30 * time.Second
+
+type cacheEntry[T any] struct {
+	value   T
+	expires time.Time
+}
+
+// lookupCache memoizes a namespaced lookup for lookupTTL, failed lookups are not cached.
+type lookupCache[T any] struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry[T]
+}
+
+func newLookupCache[T any]() *lookupCache[T] {
+	return &lookupCache[T]{entries: map[string]cacheEntry[T]{}}
+}
+
+func (c *lookupCache[T]) get(namespace string, load func() (T, error)) (T, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[namespace]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.value, nil
+	}
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	c.entries[namespace] = cacheEntry[T]{value: value, expires: time.Now().Add(lookupTTL)}
+	c.mu.Unlock()
+	return value, nil
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"crypto/x509"
+	"encoding/json"
 	"encoding/pem"
 	"fmt"
 	"net"
@@ -11,54 +12,72 @@ import (
 )
 
 type CertificateInfo struct {
-	CertificateRawInfo      `label:"Certificate Raw Info"`
-	CertificateComputedInfo `label:"Certificate Computed Info"`
+	CertificateRawInfo      `label:"Certificate Raw Info" json:"raw"`
+	CertificateComputedInfo `label:"Certificate Computed Info" json:"computed"`
+	CertificateIdentifiers  `label:"Fingerprints" json:"identifiers"`
 }
 
 type CertificateRawInfo struct {
 	// Raw Info
-	Subject            string `label:"Subject"`
-	Issuer             string `label:"Issuer"`
-	SerialNumber       string `label:"Serial Number"`
-	NotBefore          string `label:"Valid From"`
-	NotAfter           string `label:"Valid To"`
-	Signature          string `label:"Signature"`
-	SignatureAlgorithm string `label:"Signature Algorithm"`
-	PublicKeyAlgorithm string `label:"Public Key Algorithm"`
-	IsCA               bool   `label:"Is CA"`
+	Subject           string `label:"Subject" json:"subject"`
+	SubjectCommonName string `json:"subjectCommonName"`
+	Issuer            string `label:"Issuer" json:"issuer"`
+	SerialNumber      string `label:"Serial Number" json:"serialNumber"`
+	NotBefore         string `label:"Valid From" json:"notBefore"`
+	NotAfter          string `label:"Valid To" json:"notAfter"`
+	// NotAfterTime is NotAfter unformatted, e.g. for the expiry metrics
+	NotAfterTime       time.Time `json:"-"`
+	Signature          string    `label:"Signature" json:"signature"`
+	SignatureAlgorithm string    `label:"Signature Algorithm" json:"signatureAlgorithm"`
+	PublicKeyAlgorithm string    `label:"Public Key Algorithm" json:"publicKeyAlgorithm"`
+	PublicKeyBits      int       `label:"Public Key Size (bits)" json:"publicKeyBits"`
+	IsCA               bool      `label:"Is CA" json:"isCA"`
 
 	// Subject Alternative Names
-	DNSNames       []string `label:"DNS Names"`
-	EmailAddresses []string `label:"Email Addresses"`
-	IPAddresses    []string `label:"IP Addresses"`
-	URIs           []string `label:"URIs"`
+	DNSNames       []string `label:"DNS Names" json:"dnsNames"`
+	EmailAddresses []string `label:"Email Addresses" json:"emailAddresses"`
+	IPAddresses    []string `label:"IP Addresses" json:"ipAddresses"`
+	URIs           []string `label:"URIs" json:"uris"`
 
 	// Key IDs
-	SubjectKeyID   string `label:"Subject Key ID"`
-	AuthorityKeyID string `label:"Authority Key ID"`
+	SubjectKeyID   string `label:"Subject Key ID" json:"subjectKeyId"`
+	AuthorityKeyID string `label:"Authority Key ID" json:"authorityKeyId"`
 
 	// CRL / OCSP
-	CRLDistributionPoints []string `label:"CRL Distribution Points"`
-	OCSPServers           []string `label:"OCSP Servers"`
+	CRLDistributionPoints []string `label:"CRL Distribution Points" json:"crlDistributionPoints"`
+	OCSPServers           []string `label:"OCSP Servers" json:"ocspServers"`
 
 	// Usage
-	KeyUsage     string   `label:"Key Usage"`
-	ExtKeyUsages []string `label:"Extended Key Usage"`
+	KeyUsage     string   `label:"Key Usage" json:"keyUsage"`
+	ExtKeyUsages []string `label:"Extended Key Usage" json:"extKeyUsages"`
 
 	// Certificate Version
-	Version int `label:"X.509 Version"`
+	Version int `label:"X.509 Version" json:"version"`
 }
 
 type CertificateComputedInfo struct {
-	Expired             bool          `label:"Expired"`
-	TimeUntilExpiry     time.Duration `label:"Time Until Expiry"`
-	TotalValidity       time.Duration `label:"Total Validity Duration"`
-	TimeSinceIssued     time.Duration `label:"Time Since Issued"`
-	ValidityUsedPercent float64       `label:"Validity Used (%)"`
-	RemainingPercent    float64       `label:"Time Remaining (%)"`
-	ExpiryStatus        string        `label:"Expiry Status"`
-	IsSelfSigned        bool          `label:"Self-Signed"`
-	IsCurrentlyValid    bool          `label:"Currently Valid"`
+	Expired             bool     `label:"Expired" json:"expired"`
+	TimeUntilExpiry     Duration `label:"Time Until Expiry" json:"timeUntilExpiry"`
+	TotalValidity       Duration `label:"Total Validity Duration" json:"totalValidity"`
+	TimeSinceIssued     Duration `label:"Time Since Issued" json:"timeSinceIssued"`
+	ValidityUsedPercent float64  `label:"Validity Used (%)" json:"validityUsedPercent"`
+	RemainingPercent    float64  `label:"Time Remaining (%)" json:"remainingPercent"`
+	ExpiryStatus        string   `label:"Expiry Status" json:"expiryStatus"`
+	IsSelfSigned        bool     `label:"Self-Signed" json:"isSelfSigned"`
+	IsCurrentlyValid    bool     `label:"Currently Valid" json:"isCurrentlyValid"`
+	KeyMatches          string   `label:"Key Matches Certificate" json:"keyMatches"`
+}
+
+// Duration is a time.Duration that is written in its string form, e.g. "48h0m0s", so JSON,
+// YAML and CSV exports encode it the same way.
+type Duration time.Duration
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
 }
 
 var keyUsageNames = map[x509.KeyUsage]string{
@@ -86,17 +105,18 @@ var extKeyUsageMap = map[x509.ExtKeyUsage]string{
 type Status int
 
 const (
-	valid Status = iota
-	warning
-	critical
-	expired
+	StatusUnknown Status = iota
+	StatusOK
+	StatusWarning
+	StatusCritical
+	StatusExpired
 )
 
 var expiryStatusStrings = map[Status]string{
-	valid:    "OK",
-	warning:  "Warning",
-	critical: "Critical",
-	expired:  "Expired",
+	StatusOK:       "OK",
+	StatusWarning:  "Warning",
+	StatusCritical: "Critical",
+	StatusExpired:  "Expired",
 }
 
 func (s Status) String() string {
@@ -106,6 +126,16 @@ func (s Status) String() string {
 	return "Unknown"
 }
 
+// Known reports whether the expiry could be determined.
+func (s Status) Known() bool {
+	return s != StatusUnknown
+}
+
+// Severity orders statuses from unknown (lowest) to expired (highest).
+func (s Status) Severity() int {
+	return int(s)
+}
+
 func parseCertsFromString(pemStr string) ([]*x509.Certificate, error) {
 	var certs []*x509.Certificate
 	data := []byte(pemStr)
@@ -137,27 +167,30 @@ func parseCertsFromString(pemStr string) ([]*x509.Certificate, error) {
 	return certs, nil
 }
 
-func parseCertificates(certs []*x509.Certificate) []CertificateInfo {
+func parseCertificates(certs []*x509.Certificate, thresholds ExpiryThresholds) []CertificateInfo {
 	var certInfos []CertificateInfo
 	for _, cert := range certs {
-		certInfo := parseCertificate(*cert)
+		certInfo := parseCertificate(*cert, thresholds)
 		certInfos = append(certInfos, certInfo)
 	}
 	return certInfos
 }
 
-func parseCertificate(cert x509.Certificate) CertificateInfo {
-	percent, status := expiryStatusByPercentage(cert, 25.0, 10.0) // warning at 25%, critical at 10%
+func parseCertificate(cert x509.Certificate, thresholds ExpiryThresholds) CertificateInfo {
+	percent, status := expiryStatus(cert, thresholds)
 	return CertificateInfo{
 		CertificateRawInfo: CertificateRawInfo{
 			Subject:               cert.Subject.String(),
+			SubjectCommonName:     cert.Subject.CommonName,
 			Issuer:                cert.Issuer.String(),
 			SerialNumber:          cert.SerialNumber.String(),
 			NotBefore:             cert.NotBefore.Format(time.RFC1123),
 			NotAfter:              cert.NotAfter.Format(time.RFC1123),
+			NotAfterTime:          cert.NotAfter,
 			Signature:             fmt.Sprintf("%X", cert.Signature),
 			SignatureAlgorithm:    cert.SignatureAlgorithm.String(),
 			PublicKeyAlgorithm:    cert.PublicKeyAlgorithm.String(),
+			PublicKeyBits:         publicKeyBits(cert.PublicKey),
 			IsCA:                  cert.IsCA,
 			DNSNames:              cert.DNSNames,
 			EmailAddresses:        cert.EmailAddresses,
@@ -173,15 +206,17 @@ func parseCertificate(cert x509.Certificate) CertificateInfo {
 		},
 		CertificateComputedInfo: CertificateComputedInfo{
 			Expired:             time.Now().After(cert.NotAfter),
-			TimeUntilExpiry:     time.Until(cert.NotAfter),
-			TotalValidity:       cert.NotAfter.Sub(cert.NotBefore),
-			TimeSinceIssued:     time.Since(cert.NotBefore),
+			TimeUntilExpiry:     Duration(time.Until(cert.NotAfter)),
+			TotalValidity:       Duration(cert.NotAfter.Sub(cert.NotBefore)),
+			TimeSinceIssued:     Duration(time.Since(cert.NotBefore)),
 			ValidityUsedPercent: float64(time.Since(cert.NotBefore)) / float64(cert.NotAfter.Sub(cert.NotBefore)) * 100,
 			RemainingPercent:    percent,
 			ExpiryStatus:        status.String(),
 			IsSelfSigned:        cert.CheckSignatureFrom(&cert) == nil,
 			IsCurrentlyValid:    !time.Now().After(cert.NotAfter) && time.Now().After(cert.NotBefore),
+			KeyMatches:          KeyPairNotApplicable.String(),
 		},
+		CertificateIdentifiers: certificateIdentifiers(cert),
 	}
 }
 
@@ -215,25 +250,26 @@ func keyUsageToString(ku x509.KeyUsage) string {
 	return strings.Join(usages, ", ")
 }
 
-func expiryStatusByPercentage(cert x509.Certificate, warningThreshold, criticalThreshold float64) (percentRemaining float64, status Status) {
+// expiryStatus compares the remaining validity of the certificate with the thresholds.
+func expiryStatus(cert x509.Certificate, thresholds ExpiryThresholds) (percentRemaining float64, status Status) {
 	now := time.Now()
 	validityDuration := cert.NotAfter.Sub(cert.NotBefore)
 	timeRemaining := cert.NotAfter.Sub(now)
 
 	if validityDuration <= 0 {
-		return 0, critical
+		return 0, StatusCritical
 	}
 
 	percentRemaining = float64(timeRemaining) / float64(validityDuration) * 100
 
 	if now.After(cert.NotAfter) {
-		status = expired
-	} else if percentRemaining <= criticalThreshold {
-		status = critical
-	} else if percentRemaining <= warningThreshold {
-		status = warning
+		status = StatusExpired
+	} else if thresholds.Critical.reached(timeRemaining, percentRemaining) {
+		status = StatusCritical
+	} else if thresholds.Warning.reached(timeRemaining, percentRemaining) {
+		status = StatusWarning
 	} else {
-		status = valid
+		status = StatusOK
 	}
 
 	return percentRemaining, status
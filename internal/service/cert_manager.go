@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/repository"
+)
+
+const (
+	certManagerGroup  = "cert-manager.io"
+	clusterIssuerKind = "ClusterIssuer"
+	readyCondition    = "Ready"
+)
+
+// CertManagerReport describes the cert-manager Certificate that issues a TLS secret.
+type CertManagerReport struct {
+	Certificate      string   `label:"Certificate" json:"certificate"`
+	Ready            string   `label:"Ready" json:"ready"`
+	RenewalTime      string   `label:"Renewal Time" json:"renewalTime"`
+	IssuerRef        string   `label:"Issuer Ref" json:"issuerRef"`
+	IssuerReady      string   `label:"Issuer Ready" json:"issuerReady"`
+	LatestRequest    string   `label:"Latest Request" json:"latestRequest"`
+	RequestedNames   []string `label:"Requested DNS Names" json:"requestedDnsNames"`
+	MissingNames     []string `label:"Missing In Secret" json:"missingDnsNames"`
+	UnrequestedNames []string `label:"Not Requested" json:"unrequestedDnsNames"`
+}
+
+// WithCertManager links inspected secrets to the cert-manager Certificates issuing them.
+func WithCertManager(repo repository.CertManagerRepository) Option {
+	return func(s *secretsService) {
+		s.certManager = repo
+	}
+}
+
+// certManagerResources are the cert-manager resources of a namespace, cached for lookupTTL.
+type certManagerResources struct {
+	certificates []domains.CertManagerCertificate
+	requests     []domains.CertManagerCertificateRequest
+	issuers      []domains.CertManagerIssuer
+}
+
+// linkCertManager sets the CertManager report of every inspection whose secret is the
+// spec.secretName of a Certificate in the namespace, or CertManagerErr if the cert-manager
+// resources can not be listed.
+func (s secretsService) linkCertManager(ctx context.Context, namespace string, inspections []TLSSecretInspection) {
+	if s.certManager == nil {
+		return
+	}
+
+	resources, err := s.certManagerCache.get(namespace, func() (certManagerResources, error) {
+		return s.certManagerResources(ctx, namespace)
+	})
+	if err != nil {
+		err = fmt.Errorf("can not link cert-manager resources: %w", err)
+	}
+
+	for i, inspection := range inspections {
+		if inspection.Err != nil || inspection.Kind != "" || len(inspection.Certificates) == 0 {
+			continue
+		}
+		if err != nil {
+			inspections[i].CertManagerErr = err
+			continue
+		}
+		index := slices.IndexFunc(resources.certificates, func(c domains.CertManagerCertificate) bool {
+			return c.Namespace == inspection.Namespace && c.SecretName == inspection.Name
+		})
+		if index != -1 {
+			report := newCertManagerReport(resources.certificates[index], resources.requests, resources.issuers, inspection.Certificates[0])
+			inspections[i].CertManager = &report
+		}
+	}
+}
+
+func (s secretsService) certManagerResources(ctx context.Context, namespace string) (certManagerResources, error) {
+	certificates, err := s.certManager.GetCertificates(ctx, namespace)
+	if err != nil || len(certificates) == 0 {
+		return certManagerResources{}, err
+	}
+
+	requests, err := s.certManager.GetCertificateRequests(ctx, namespace)
+	if err != nil {
+		return certManagerResources{}, err
+	}
+
+	issuers, err := s.certManager.GetIssuers(ctx, namespace)
+	if err != nil {
+		return certManagerResources{}, err
+	}
+
+	return certManagerResources{certificates: certificates, requests: requests, issuers: issuers}, nil
+}
+
+func newCertManagerReport(
+	certificate domains.CertManagerCertificate,
+	requests []domains.CertManagerCertificateRequest,
+	issuers []domains.CertManagerIssuer,
+	leaf CertificateInfo,
+) CertManagerReport {
+	report := CertManagerReport{
+		Certificate:    certificate.Name,
+		Ready:          formatCondition(certificate.Conditions, readyCondition),
+		RenewalTime:    "Unknown",
+		IssuerRef:      formatIssuerRef(certificate.IssuerRef),
+		IssuerReady:    issuerReady(certificate, issuers),
+		LatestRequest:  "None",
+		RequestedNames: certificate.DNSNames,
+	}
+
+	if certificate.RenewalTime != nil {
+		report.RenewalTime = certificate.RenewalTime.Format(time.RFC1123)
+	}
+
+	var latest *domains.CertManagerCertificateRequest
+	for i, request := range requests {
+		if request.Namespace == certificate.Namespace && request.CertificateName == certificate.Name &&
+			(latest == nil || request.Revision > latest.Revision) {
+			latest = &requests[i]
+		}
+	}
+	if latest != nil {
+		report.LatestRequest = latest.Name + ": " + formatCondition(latest.Conditions, readyCondition)
+	}
+
+	for _, name := range certificate.DNSNames {
+		if !containsFold(leaf.DNSNames, name) {
+			report.MissingNames = append(report.MissingNames, name)
+		}
+	}
+	for _, name := range leaf.DNSNames {
+		if !containsFold(certificate.DNSNames, name) && !strings.EqualFold(name, certificate.CommonName) {
+			report.UnrequestedNames = append(report.UnrequestedNames, name)
+		}
+	}
+
+	return report
+}
+
+func issuerReady(certificate domains.CertManagerCertificate, issuers []domains.CertManagerIssuer) string {
+	ref := certificate.IssuerRef
+	if ref.Group != "" && ref.Group != certManagerGroup {
+		return "External issuer"
+	}
+
+	for _, issuer := range issuers {
+		if issuer.Name != ref.Name {
+			continue
+		}
+		if ref.Kind == clusterIssuerKind && issuer.Namespace == "" ||
+			ref.Kind != clusterIssuerKind && issuer.Namespace == certificate.Namespace {
+			return formatCondition(issuer.Conditions, readyCondition)
+		}
+	}
+	return "Not found"
+}
+
+func formatIssuerRef(ref domains.IssuerRef) string {
+	kind := ref.Kind
+	if kind == "" {
+		kind = "Issuer"
+	}
+	group := ref.Group
+	if group == "" {
+		group = certManagerGroup
+	}
+	return fmt.Sprintf("%s/%s (%s)", kind, ref.Name, group)
+}
+
+// formatCondition renders a condition as "Status (Reason: Message)".
+func formatCondition(conditions []domains.Condition, conditionType string) string {
+	for _, condition := range conditions {
+		if condition.Type != conditionType {
+			continue
+		}
+		text := condition.Status
+		if condition.Reason != "" {
+			text += " (" + condition.Reason
+			if condition.Message != "" {
+				text += ": " + condition.Message
+			}
+			text += ")"
+		}
+		return text
+	}
+	return "Unknown"
+}
+
+func containsFold(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(v string) bool {
+		return strings.EqualFold(v, value)
+	})
+}
@@ -0,0 +1,131 @@
+package service_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/repository"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+func TestInspectTLSSecretCertManager(t *testing.T) {
+	now := time.Now()
+	leaf := issueTestCertificate(t, "example.com", nil, false, now.Add(-time.Hour), now.Add(24*time.Hour))
+	renewal := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	secretRepo := repository.NewMockRepository(nil, func(ctx context.Context, namespace, name string) (domains.SecretInfo, error) {
+		return domains.SecretInfo{Name: name, Namespace: namespace, TLSCert: pemBundle(leaf)}, nil
+	}, nil)
+
+	certificate := domains.CertManagerCertificate{
+		K8SResourceID: domains.K8SResourceID{Name: "example", Namespace: "default"},
+		SecretName:    "example-tls",
+		DNSNames:      []string{"example.com", "www.example.com"},
+		IssuerRef:     domains.IssuerRef{Name: "letsencrypt", Kind: "ClusterIssuer"},
+		RenewalTime:   &renewal,
+		Conditions:    []domains.Condition{{Type: "Ready", Status: "True", Reason: "Ready", Message: "Certificate is up to date"}},
+	}
+
+	tests := []struct {
+		name           string
+		secretName     string
+		certificates   []domains.CertManagerCertificate
+		requests       []domains.CertManagerCertificateRequest
+		issuers        []domains.CertManagerIssuer
+		certManagerErr error
+		expectedReport *service.CertManagerReport
+		expectedErr    bool
+		expectedLists  int
+	}{
+		{
+			name:          "Should not link secrets that are not the secretName of a Certificate",
+			secretName:    "other-tls",
+			certificates:  []domains.CertManagerCertificate{certificate},
+			expectedLists: 1,
+		},
+		{
+			name:         "Should link the Certificate by its secretName, its latest request and issuer",
+			secretName:   "example-tls",
+			certificates: []domains.CertManagerCertificate{certificate},
+			requests: []domains.CertManagerCertificateRequest{
+				{K8SResourceID: domains.K8SResourceID{Name: "example-1", Namespace: "default"}, CertificateName: "example", Revision: 1},
+				{
+					K8SResourceID:   domains.K8SResourceID{Name: "example-2", Namespace: "default"},
+					CertificateName: "example",
+					Revision:        2,
+					Conditions:      []domains.Condition{{Type: "Ready", Status: "False", Reason: "Pending"}},
+				},
+				{K8SResourceID: domains.K8SResourceID{Name: "unrelated-3", Namespace: "default"}, CertificateName: "unrelated", Revision: 3},
+			},
+			issuers: []domains.CertManagerIssuer{
+				{K8SResourceID: domains.K8SResourceID{Name: "letsencrypt", Namespace: "default"}, Kind: "Issuer"},
+				{K8SResourceID: domains.K8SResourceID{Name: "letsencrypt"}, Kind: "ClusterIssuer", Conditions: []domains.Condition{{Type: "Ready", Status: "True"}}},
+			},
+			expectedReport: &service.CertManagerReport{
+				Certificate:    "example",
+				Ready:          "True (Ready: Certificate is up to date)",
+				RenewalTime:    "Tue, 01 Jan 2030 00:00:00 UTC",
+				IssuerRef:      "ClusterIssuer/letsencrypt (cert-manager.io)",
+				IssuerReady:    "True",
+				LatestRequest:  "example-2: False (Pending)",
+				RequestedNames: []string{"example.com", "www.example.com"},
+				MissingNames:   []string{"www.example.com"},
+			},
+			expectedLists: 1,
+		},
+		{
+			name:           "Should keep the inspection when the cert-manager resources can not be listed",
+			secretName:     "example-tls",
+			certManagerErr: errRepo,
+			expectedErr:    true,
+			expectedLists:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lists := 0
+			certManagerRepo := repository.NewMockCertManagerRepository(
+				func(ctx context.Context, namespace string) ([]domains.CertManagerCertificate, error) {
+					lists++
+					return tt.certificates, tt.certManagerErr
+				},
+				func(ctx context.Context, namespace string) ([]domains.CertManagerCertificateRequest, error) {
+					return tt.requests, nil
+				},
+				func(ctx context.Context, namespace string) ([]domains.CertManagerIssuer, error) {
+					return tt.issuers, nil
+				},
+			)
+
+			svc := service.NewSecretsService(secretRepo, service.WithCertManager(certManagerRepo))
+
+			// the second inspection reuses the cached resources, failed lookups are retried
+			for range 2 {
+				inspection, err := svc.InspectTLSSecret(context.Background(), "default", tt.secretName)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if (inspection.CertManagerErr != nil) != tt.expectedErr {
+					t.Errorf("expected cert-manager error: %v, got %v", tt.expectedErr, inspection.CertManagerErr)
+				}
+
+				if len(inspection.Certificates) == 0 {
+					t.Errorf("expected the certificates of the secret")
+				}
+
+				if !reflect.DeepEqual(inspection.CertManager, tt.expectedReport) {
+					t.Errorf("expected report %+v, got %+v", tt.expectedReport, inspection.CertManager)
+				}
+			}
+
+			if lists != tt.expectedLists {
+				t.Errorf("expected %d Certificate lists, got %d", tt.expectedLists, lists)
+			}
+		})
+	}
+}
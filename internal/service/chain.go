@@ -0,0 +1,255 @@
+package service
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+type ChainStatus int
+
+const (
+	chainValid ChainStatus = iota
+	chainValidWithWarnings
+	chainInvalid
+)
+
+var chainStatusStrings = map[ChainStatus]string{
+	chainValid:             "Valid",
+	chainValidWithWarnings: "Valid (with warnings)",
+	chainInvalid:           "Invalid",
+}
+
+func (c ChainStatus) String() string {
+	if str, ok := chainStatusStrings[c]; ok {
+		return str
+	}
+	return "Unknown"
+}
+
+// ChainReport is the outcome of building and verifying the certificate paths of a secret.
+type ChainReport struct {
+	Status  string   `label:"Status" json:"status"`
+	Trusted bool     `label:"Trusted" json:"trusted"`
+	Paths   []string `label:"Verified Paths" json:"paths"`
+	Issues  []string `label:"Issues" json:"issues"`
+}
+
+// LoadTrustBundle reads the certificates of a PEM bundle from disk.
+func LoadTrustBundle(path string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("can not read trust bundle %s: %w", path, err)
+	}
+
+	certs, err := parseCertsFromString(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("can not parse trust bundle %s: %w", path, err)
+	}
+
+	return certs, nil
+}
+
+// verifyChain builds every path from the leaf (the first certificate) to a trusted root,
+// using the remaining certificates as intermediates and caCerts as additional trust anchors,
+// and reports ordering, issuer and validity problems of the certificates in the secret.
+func verifyChain(certs []*x509.Certificate, caCerts []*x509.Certificate, trustBundle []*x509.Certificate, now time.Time) ChainReport {
+	roots, err := rootsWithCA(trustBundle, caCerts)
+	var chains [][]*x509.Certificate
+	if err == nil {
+		chains, err = buildPaths(certs, roots, now)
+	}
+
+	var issues []string
+	issues = append(issues, linkIssues(certs, caCerts, trustBundle, roots, chains, now)...)
+	issues = append(issues, validityIssues(certs, now)...)
+	if err != nil {
+		issues = append(issues, fmt.Sprintf("path building failed: %v", err))
+	}
+
+	paths := make([]string, 0, len(chains))
+	for _, chain := range chains {
+		paths = append(paths, strings.Join(joinToStringSlice(chain, certDisplayName), " → "))
+	}
+
+	report := ChainReport{
+		Trusted: err == nil,
+		Paths:   paths,
+		Issues:  issues,
+	}
+
+	switch {
+	case err != nil:
+		report.Status = chainInvalid.String()
+	case len(issues) > 0:
+		report.Status = chainValidWithWarnings.String()
+	default:
+		report.Status = chainValid.String()
+	}
+
+	return report
+}
+
+// buildPaths verifies the leaf (the first certificate) against the roots, using the remaining
+// certificates as intermediates.
+func buildPaths(certs []*x509.Certificate, roots *x509.CertPool, now time.Time) ([][]*x509.Certificate, error) {
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	return certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+}
+
+func rootsWithCA(trustBundle []*x509.Certificate, caCerts []*x509.Certificate) (*x509.CertPool, error) {
+	var roots *x509.CertPool
+	if trustBundle != nil {
+		roots = x509.NewCertPool()
+		for _, cert := range trustBundle {
+			roots.AddCert(cert)
+		}
+	} else {
+		system, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, fmt.Errorf("can not load system trust bundle: %w", err)
+		}
+		roots = system
+	}
+
+	for _, cert := range caCerts {
+		roots.AddCert(cert)
+	}
+
+	return roots, nil
+}
+
+func linkIssues(certs []*x509.Certificate, caCerts []*x509.Certificate, trustBundle []*x509.Certificate, roots *x509.CertPool, chains [][]*x509.Certificate, now time.Time) []string {
+	var issues []string
+
+	issuers := make([]int, len(certs))
+	for i, cert := range certs {
+		issuers[i] = -1
+		if !isSelfIssued(cert) {
+			issuers[i] = findIssuer(cert, certs)
+		}
+	}
+
+	// walk the leaf's path to find certificates that do not belong to it
+	used := make([]bool, len(certs))
+	for idx := 0; idx != -1 && !used[idx]; idx = issuers[idx] {
+		used[idx] = true
+	}
+
+	for i, cert := range certs {
+		if isSelfIssued(cert) {
+			continue
+		}
+
+		issuerIdx := issuers[i]
+		switch {
+		case issuerIdx == -1 && isIssuedByAny(cert, caCerts):
+			// chain ends at a certificate from ca.crt
+		case issuerIdx == -1 && hasSubject(certs, cert.RawIssuer):
+			issues = append(issues, fmt.Sprintf("wrong issuer: certificate #%d (%s) is not signed by the certificate named as its issuer", i+1, certDisplayName(cert)))
+		case issuerIdx == -1 && (isIssuedByAny(cert, trustBundle) || anchored(cert, roots, chains, now)):
+			// chain ends at a trust anchor, the system roots can not be enumerated so only
+			// links that path building anchored are accepted
+		case issuerIdx == -1:
+			issues = append(issues, fmt.Sprintf("missing intermediate: issuer %q of certificate #%d is not in the secret", cert.Issuer.String(), i+1))
+		case issuerIdx != i+1:
+			issues = append(issues, fmt.Sprintf("out of order: issuer of certificate #%d is at position #%d instead of #%d", i+1, issuerIdx+1, i+2))
+		}
+
+		// a following certificate that issues nothing in the secret is a stray link
+		next := i + 1
+		if issuerIdx == -1 && next < len(certs) && !used[next] && !bytes.Equal(cert.RawIssuer, certs[next].RawSubject) {
+			issues = append(issues, fmt.Sprintf("wrong issuer link: certificate #%d (%s) does not issue certificate #%d", next+1, certDisplayName(certs[next]), i+1))
+		}
+	}
+
+	for i, isUsed := range used {
+		if !isUsed && !isSelfIssued(certs[i]) {
+			issues = append(issues, fmt.Sprintf("unused certificate: #%d (%s) is not part of the leaf's chain", i+1, certDisplayName(certs[i])))
+		}
+	}
+
+	return issues
+}
+
+// anchored reports whether a verified chain continues from cert to a root, or, when the chain as
+// a whole failed, whether cert alone verifies against the roots.
+func anchored(cert *x509.Certificate, roots *x509.CertPool, chains [][]*x509.Certificate, now time.Time) bool {
+	for _, chain := range chains {
+		for i, link := range chain[:len(chain)-1] {
+			if link.Equal(cert) && bytes.Equal(cert.RawIssuer, chain[i+1].RawSubject) {
+				return true
+			}
+		}
+	}
+
+	if roots == nil {
+		return false
+	}
+	_, err := cert.Verify(x509.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: now,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err == nil
+}
+
+func validityIssues(certs []*x509.Certificate, now time.Time) []string {
+	var issues []string
+	for i, cert := range certs {
+		if now.After(cert.NotAfter) {
+			issues = append(issues, fmt.Sprintf("expired link: certificate #%d (%s) expired on %s", i+1, certDisplayName(cert), cert.NotAfter.Format(time.RFC1123)))
+		} else if now.Before(cert.NotBefore) {
+			issues = append(issues, fmt.Sprintf("not yet valid link: certificate #%d (%s) is valid from %s", i+1, certDisplayName(cert), cert.NotBefore.Format(time.RFC1123)))
+		}
+	}
+	return issues
+}
+
+func findIssuer(cert *x509.Certificate, candidates []*x509.Certificate) int {
+	for i, candidate := range candidates {
+		if candidate == cert {
+			continue
+		}
+		if bytes.Equal(cert.RawIssuer, candidate.RawSubject) && cert.CheckSignatureFrom(candidate) == nil {
+			return i
+		}
+	}
+	return -1
+}
+
+func isIssuedByAny(cert *x509.Certificate, candidates []*x509.Certificate) bool {
+	return findIssuer(cert, candidates) != -1
+}
+
+func hasSubject(certs []*x509.Certificate, rawSubject []byte) bool {
+	for _, cert := range certs {
+		if bytes.Equal(cert.RawSubject, rawSubject) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSelfIssued(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject)
+}
+
+func certDisplayName(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	return cert.Subject.String()
+}
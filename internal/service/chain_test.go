@@ -0,0 +1,198 @@
+package service_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/repository"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func issueTestCertificate(t *testing.T, cn string, parent *testCA, isCA bool, notBefore, notAfter time.Time) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		DNSNames:              []string{cn},
+	}
+
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	return &testCA{cert: cert, key: key}
+}
+
+func pemBundle(certs ...*testCA) []byte {
+	var bundle []byte
+	for _, c := range certs {
+		bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw})...)
+	}
+	return bundle
+}
+
+func TestInspectTLSSecretChain(t *testing.T) {
+	now := time.Now()
+	from, to := now.Add(-time.Hour), now.Add(24*time.Hour)
+
+	root := issueTestCertificate(t, "root", nil, true, from, to)
+	int2 := issueTestCertificate(t, "intermediate-2", root, true, from, to)
+	int1 := issueTestCertificate(t, "intermediate-1", int2, true, from, to)
+	leaf := issueTestCertificate(t, "leaf", int1, false, from, to)
+
+	otherRoot := issueTestCertificate(t, "other-root", nil, true, from, to)
+	otherInt := issueTestCertificate(t, "other-intermediate", otherRoot, true, from, to)
+
+	expiredInt := issueTestCertificate(t, "expired-intermediate", root, true, now.Add(-48*time.Hour), now.Add(-24*time.Hour))
+	leafOfExpired := issueTestCertificate(t, "leaf", expiredInt, false, now.Add(-36*time.Hour), to)
+
+	// carries the name of intermediate-1 but a different key
+	impostor := issueTestCertificate(t, "intermediate-1", int2, true, from, to)
+
+	trustBundle := []*x509.Certificate{root.cert}
+
+	tests := []struct {
+		name            string
+		tlsCert         []byte
+		caCert          []byte
+		trustBundle     []*x509.Certificate
+		expectedStatus  string
+		expectedTrusted bool
+		expectedPaths   []string
+		expectedIssues  []string
+	}{
+		{
+			name:            "Should verify a complete chain anchored in ca.crt",
+			tlsCert:         pemBundle(leaf, int1, int2),
+			caCert:          pemBundle(root),
+			trustBundle:     []*x509.Certificate{},
+			expectedStatus:  "Valid",
+			expectedTrusted: true,
+			expectedPaths:   []string{"leaf → intermediate-1 → intermediate-2 → root"},
+		},
+		{
+			name:            "Should verify a complete chain anchored in the trust bundle",
+			tlsCert:         pemBundle(leaf, int1, int2),
+			trustBundle:     trustBundle,
+			expectedStatus:  "Valid",
+			expectedTrusted: true,
+			expectedPaths:   []string{"leaf → intermediate-1 → intermediate-2 → root"},
+		},
+		{
+			name:            "Should report out of order certificates",
+			tlsCert:         pemBundle(leaf, int2, int1),
+			trustBundle:     trustBundle,
+			expectedStatus:  "Valid (with warnings)",
+			expectedTrusted: true,
+			expectedPaths:   []string{"leaf → intermediate-1 → intermediate-2 → root"},
+			expectedIssues:  []string{"out of order", "out of order"},
+		},
+		{
+			name:           "Should report a missing intermediate",
+			tlsCert:        pemBundle(leaf, int2),
+			trustBundle:    trustBundle,
+			expectedStatus: "Invalid",
+			expectedIssues: []string{"missing intermediate", "wrong issuer link", "unused certificate", "path building failed"},
+		},
+		{
+			name:           "Should report a wrong issuer link",
+			tlsCert:        pemBundle(int1, otherInt),
+			trustBundle:    trustBundle,
+			expectedStatus: "Invalid",
+			expectedIssues: []string{"missing intermediate", "wrong issuer link", "missing intermediate", "unused certificate", "path building failed"},
+		},
+		{
+			name:           "Should report a missing intermediate without a trust bundle",
+			tlsCert:        pemBundle(leaf, int1),
+			expectedStatus: "Invalid",
+			expectedIssues: []string{"missing intermediate", "path building failed"},
+		},
+		{
+			name:           "Should report a wrong issuer without a trust bundle",
+			tlsCert:        pemBundle(leaf, impostor),
+			expectedStatus: "Invalid",
+			expectedIssues: []string{"wrong issuer", "missing intermediate", "unused certificate", "path building failed"},
+		},
+		{
+			name:           "Should report an expired link",
+			tlsCert:        pemBundle(leafOfExpired, expiredInt),
+			trustBundle:    trustBundle,
+			expectedStatus: "Invalid",
+			expectedIssues: []string{"expired link", "path building failed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockRepository(nil, func(ctx context.Context, namespace, name string) (domains.SecretInfo, error) {
+				return domains.SecretInfo{Name: name, Namespace: namespace, TLSCert: tt.tlsCert, CACert: tt.caCert}, nil
+			}, nil)
+
+			svc := service.NewSecretsService(mockRepo, service.WithTrustBundle(tt.trustBundle))
+			inspection, err := svc.InspectTLSSecret(context.Background(), "default", "tls-secret")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			chain := inspection.Chain
+			if chain.Status != tt.expectedStatus {
+				t.Errorf("expected status %q, got %q (issues: %v)", tt.expectedStatus, chain.Status, chain.Issues)
+			}
+
+			if chain.Trusted != tt.expectedTrusted {
+				t.Errorf("expected trusted %v, got %v", tt.expectedTrusted, chain.Trusted)
+			}
+
+			if strings.Join(chain.Paths, ";") != strings.Join(tt.expectedPaths, ";") {
+				t.Errorf("expected paths %v, got %v", tt.expectedPaths, chain.Paths)
+			}
+
+			if len(chain.Issues) != len(tt.expectedIssues) {
+				t.Fatalf("expected issues %v, got %v", tt.expectedIssues, chain.Issues)
+			}
+			for i, issue := range tt.expectedIssues {
+				if !strings.HasPrefix(chain.Issues[i], issue) {
+					t.Errorf("expected issue %d to start with %q, got %q", i, issue, chain.Issues[i])
+				}
+			}
+		})
+	}
+}
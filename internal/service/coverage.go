@@ -0,0 +1,49 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/codechamp1/certlens/internal/domains"
+)
+
+// uncoveredHosts returns the hosts that the references serve with the secret but that are not
+// covered by the SANs of its leaf certificate, as "host (Kind namespace/name)".
+func uncoveredHosts(sans []string, references []domains.SecretReference) []string {
+	var uncovered []string
+	for _, reference := range references {
+		for _, host := range reference.Hosts {
+			if !hostCovered(sans, host) {
+				uncovered = append(uncovered, host+" ("+referenceName(reference)+")")
+			}
+		}
+	}
+	return uncovered
+}
+
+// hostCovered matches the host against the DNS SANs following RFC 6125: a wildcard is only
+// allowed as the complete left-most label and matches exactly one label, so *.example.com
+// covers www.example.com but neither example.com nor a.b.example.com. Wildcard hosts, such
+// as a Gateway listener hostname, are only covered by the same wildcard.
+func hostCovered(sans []string, host string) bool {
+	host = normalizeHostname(host)
+	for _, san := range sans {
+		san = normalizeHostname(san)
+		if san == host {
+			return true
+		}
+
+		suffix, ok := strings.CutPrefix(san, "*.")
+		if !ok || strings.Count(suffix, ".") == 0 || strings.HasPrefix(host, "*.") {
+			continue // no wildcard, or a wildcard spanning a whole public suffix
+		}
+		label, rest, found := strings.Cut(host, ".")
+		if found && label != "" && rest == suffix {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeHostname(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".")
+}
@@ -0,0 +1,62 @@
+package service_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/repository"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+func TestUncoveredHosts(t *testing.T) {
+	now := time.Now()
+	wildcard := issueTestCertificate(t, "*.example.com", nil, false, now.Add(-time.Hour), now.Add(24*time.Hour))
+
+	secretRepo := repository.NewMockRepository(nil, func(ctx context.Context, namespace, name string) (domains.SecretInfo, error) {
+		return domains.SecretInfo{Name: name, Namespace: namespace, TLSCert: pemBundle(wildcard)}, nil
+	}, nil)
+
+	tests := []struct {
+		name      string
+		host      string
+		uncovered bool
+	}{
+		{name: "Should cover a host matching the wildcard", host: "www.example.com"},
+		{name: "Should match case insensitively and ignore a trailing dot", host: "WWW.Example.com."},
+		{name: "Should cover the same wildcard host", host: "*.example.com"},
+		{name: "Should not cover the bare domain with a wildcard", host: "example.com", uncovered: true},
+		{name: "Should not cover more than one label with a wildcard", host: "a.b.example.com", uncovered: true},
+		{name: "Should not cover a broader wildcard host", host: "*.com", uncovered: true},
+		{name: "Should not cover another domain", host: "www.example.org", uncovered: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			references := repository.NewMockReferencesRepository(func(ctx context.Context, namespace string) ([]domains.SecretReference, error) {
+				return []domains.SecretReference{{
+					K8SResourceID: domains.K8SResourceID{Name: "web", Namespace: "default"},
+					Kind:          "Ingress",
+					Secret:        domains.K8SResourceID{Name: "web-tls", Namespace: "default"},
+					Hosts:         []string{tt.host},
+				}}, nil
+			})
+
+			svc := service.NewSecretsService(secretRepo, service.WithReferences(references))
+			inspection, err := svc.InspectTLSSecret(context.Background(), "default", "web-tls")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var expected []string
+			if tt.uncovered {
+				expected = []string{tt.host + " (Ingress default/web)"}
+			}
+			if !reflect.DeepEqual(inspection.UncoveredHosts, expected) {
+				t.Errorf("expected uncovered hosts %v, got %v", expected, inspection.UncoveredHosts)
+			}
+		})
+	}
+}
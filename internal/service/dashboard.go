@@ -0,0 +1,123 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	dashboardHorizon    = 90 * 24 * time.Hour
+	dashboardBucketSize = 10 * 24 * time.Hour
+	dashboardTopIssuers = 5
+)
+
+// DashboardEntry is a single line of the dashboard. Match selects the secrets counted by it,
+// so the UI can drill down into them.
+type DashboardEntry struct {
+	Label string
+	Count int
+	Match func(TLSSecretSummary) bool
+}
+
+// Dashboard aggregates the leaf certificates of all listed TLS secrets.
+type Dashboard struct {
+	Total       DashboardEntry
+	Statuses    []DashboardEntry
+	Expiring    []DashboardEntry // histogram of the next 90 days
+	TopIssuers  []DashboardEntry
+	SelfSigned  DashboardEntry
+	KeyMismatch DashboardEntry
+	Unused      DashboardEntry
+	// Clusters counts the secrets per cluster, it is empty unless several clusters are inspected.
+	Clusters []DashboardEntry
+}
+
+func NewDashboard(summaries []TLSSecretSummary) Dashboard {
+	dashboard := Dashboard{
+		Total: DashboardEntry{Label: "All secrets", Match: func(TLSSecretSummary) bool { return true }},
+		SelfSigned: DashboardEntry{Label: "Self-signed", Match: func(s TLSSecretSummary) bool {
+			return s.SelfSigned
+		}},
+		KeyMismatch: DashboardEntry{Label: "Key mismatch", Match: func(s TLSSecretSummary) bool {
+			return s.KeyPair.Mismatch()
+		}},
+		Unused: DashboardEntry{Label: "Unused", Match: func(s TLSSecretSummary) bool {
+			return s.Unused
+		}},
+	}
+
+	for _, status := range []Status{StatusOK, StatusWarning, StatusCritical, StatusExpired, StatusUnknown} {
+		dashboard.Statuses = append(dashboard.Statuses, DashboardEntry{Label: status.String(), Match: func(s TLSSecretSummary) bool {
+			return s.Expiry == status
+		}})
+	}
+
+	for start := time.Duration(0); start < dashboardHorizon; start += dashboardBucketSize {
+		end := start + dashboardBucketSize
+		dashboard.Expiring = append(dashboard.Expiring, DashboardEntry{
+			Label: fmt.Sprintf("%d-%dd", int(start.Hours()/24), int(end.Hours()/24)),
+			Match: func(s TLSSecretSummary) bool {
+				return s.Expiry.Known() && s.TimeUntilExpiry >= start && s.TimeUntilExpiry < end
+			},
+		})
+	}
+
+	issuers := map[string]int{}
+	for _, summary := range summaries {
+		if summary.Expiry.Known() {
+			issuers[summary.Issuer]++
+		}
+	}
+	for issuer := range issuers {
+		dashboard.TopIssuers = append(dashboard.TopIssuers, DashboardEntry{Label: issuer, Match: func(s TLSSecretSummary) bool {
+			return s.Expiry.Known() && s.Issuer == issuer
+		}})
+	}
+
+	clusters := map[string]bool{}
+	for _, summary := range summaries {
+		if summary.Cluster != "" {
+			clusters[summary.Cluster] = true
+		}
+	}
+	for cluster := range clusters {
+		dashboard.Clusters = append(dashboard.Clusters, DashboardEntry{Label: cluster, Match: func(s TLSSecretSummary) bool {
+			return s.Cluster == cluster
+		}})
+	}
+	sort.Slice(dashboard.Clusters, func(i, j int) bool {
+		return dashboard.Clusters[i].Label < dashboard.Clusters[j].Label
+	})
+
+	for _, summary := range summaries {
+		dashboard.Total.count(summary)
+		dashboard.SelfSigned.count(summary)
+		dashboard.KeyMismatch.count(summary)
+		dashboard.Unused.count(summary)
+		for _, entries := range [][]DashboardEntry{dashboard.Statuses, dashboard.Expiring, dashboard.TopIssuers, dashboard.Clusters} {
+			for i := range entries {
+				entries[i].count(summary)
+			}
+		}
+	}
+
+	sort.Slice(dashboard.TopIssuers, func(i, j int) bool {
+		a, b := dashboard.TopIssuers[i], dashboard.TopIssuers[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Label < b.Label
+	})
+	if len(dashboard.TopIssuers) > dashboardTopIssuers {
+		dashboard.TopIssuers = dashboard.TopIssuers[:dashboardTopIssuers]
+	}
+
+	return dashboard
+}
+
+func (e *DashboardEntry) count(summary TLSSecretSummary) {
+	if e.Match(summary) {
+		e.Count++
+	}
+}
@@ -0,0 +1,105 @@
+package service_test
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/repository"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+func TestNewDashboard(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+
+	root := issueTestCertificate(t, "root", nil, true, now.Add(-time.Hour), now.Add(365*day))
+	critical := issueTestCertificate(t, "critical", root, false, now.Add(-360*day), now.Add(5*day))
+	healthy := issueTestCertificate(t, "healthy", root, false, now.Add(-time.Hour), now.Add(100*day))
+	expired := issueTestCertificate(t, "expired", root, false, now.Add(-30*day), now.Add(-day))
+
+	rootKey, err := x509.MarshalECPrivateKey(root.key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	secrets := []domains.SecretInfo{
+		{Name: "root", Namespace: "default", TLSCert: pemBundle(root)},
+		{Name: "critical", Namespace: "default", TLSCert: pemBundle(critical)},
+		{Name: "healthy", Namespace: "default", TLSCert: pemBundle(healthy), TLSKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: rootKey})},
+		{Name: "expired", Namespace: "default", TLSCert: pemBundle(expired)},
+		{Name: "broken", Namespace: "default", TLSCert: []byte("not a certificate")},
+	}
+
+	repo := repository.NewMockRepository(func(ctx context.Context, namespace string) ([]domains.SecretInfo, error) {
+		return secrets, nil
+	}, nil, nil)
+
+	summaries, err := service.NewSecretsService(repo).ListTLSSecrets(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dashboard := service.NewDashboard(summaries)
+
+	counts := map[string]int{}
+	for _, entry := range dashboard.Statuses {
+		counts[entry.Label] = entry.Count
+	}
+
+	tests := []struct {
+		name     string
+		actual   int
+		expected int
+	}{
+		{name: "total", actual: dashboard.Total.Count, expected: 5},
+		{name: "OK", actual: counts["OK"], expected: 2},
+		{name: "Warning", actual: counts["Warning"], expected: 0},
+		{name: "Critical", actual: counts["Critical"], expected: 1},
+		{name: "Expired", actual: counts["Expired"], expected: 1},
+		{name: "Unknown", actual: counts["Unknown"], expected: 1},
+		{name: "expiring in 0-10d", actual: dashboard.Expiring[0].Count, expected: 1},
+		{name: "self-signed", actual: dashboard.SelfSigned.Count, expected: 1},
+		{name: "key mismatch", actual: dashboard.KeyMismatch.Count, expected: 1},
+	}
+
+	for _, tt := range tests {
+		if tt.actual != tt.expected {
+			t.Errorf("expected %d %s secrets, got %d", tt.expected, tt.name, tt.actual)
+		}
+	}
+
+	if len(dashboard.Expiring) != 9 || dashboard.Expiring[8].Label != "80-90d" {
+		t.Errorf("expected 9 buckets up to 90 days, got %+v", dashboard.Expiring)
+	}
+
+	if len(dashboard.TopIssuers) != 1 || dashboard.TopIssuers[0].Label != "root" || dashboard.TopIssuers[0].Count != 4 {
+		t.Errorf("expected root to have issued 4 certificates, got %+v", dashboard.TopIssuers)
+	}
+
+	for _, summary := range summaries {
+		if dashboard.Expiring[0].Match(summary) != (summary.Name == "critical") {
+			t.Errorf("expected only the critical secret to match the 0-10d bucket, %s did not", summary.Name)
+		}
+	}
+}
+
+func TestNewDashboardClusters(t *testing.T) {
+	summaries := []service.TLSSecretSummary{
+		{K8SResourceID: domains.K8SResourceID{Name: "a", Cluster: "staging"}},
+		{K8SResourceID: domains.K8SResourceID{Name: "b", Cluster: "prod"}},
+		{K8SResourceID: domains.K8SResourceID{Name: "c", Cluster: "prod"}},
+	}
+
+	dashboard := service.NewDashboard(summaries)
+	if len(dashboard.Clusters) != 2 || dashboard.Clusters[0].Label != "prod" || dashboard.Clusters[0].Count != 2 || dashboard.Clusters[1].Count != 1 {
+		t.Errorf("expected 2 secrets in prod and 1 in staging, got %+v", dashboard.Clusters)
+	}
+
+	if clusters := service.NewDashboard(summaries[:0]).Clusters; len(clusters) != 0 {
+		t.Errorf("expected no clusters without multi-cluster summaries, got %+v", clusters)
+	}
+}
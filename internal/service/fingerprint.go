@@ -0,0 +1,167 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// CertificateIdentifiers are the digests used to match a certificate against pinning
+// configurations and load balancer listings.
+type CertificateIdentifiers struct {
+	SHA256Fingerprint string `label:"SHA-256 Fingerprint" json:"sha256Fingerprint"`
+	SHA1Fingerprint   string `label:"SHA-1 Fingerprint" json:"sha1Fingerprint"`
+	SPKIPin           string `label:"SPKI SHA-256 Pin" json:"spkiSha256Pin"`
+	SubjectHash       string `label:"Subject Hash" json:"subjectHash"`
+	IssuerHash        string `label:"Issuer Hash" json:"issuerHash"`
+}
+
+func certificateIdentifiers(cert x509.Certificate) CertificateIdentifiers {
+	sha1Sum := sha1.Sum(cert.Raw)
+	pin := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return CertificateIdentifiers{
+		SHA256Fingerprint: sha256Fingerprint(&cert),
+		SHA1Fingerprint:   colonHex(sha1Sum[:]),
+		SPKIPin:           base64.StdEncoding.EncodeToString(pin[:]),
+		SubjectHash:       nameHash(cert.RawSubject),
+		IssuerHash:        nameHash(cert.RawIssuer),
+	}
+}
+
+// sha256Fingerprint formats the SHA-256 digest of the DER certificate like openssl, AB:CD:...
+func sha256Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return colonHex(sum[:])
+}
+
+func colonHex(sum []byte) string {
+	hex := make([]string, len(sum))
+	for i, b := range sum {
+		hex[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(hex, ":")
+}
+
+type rawAttribute struct {
+	Type  asn1.ObjectIdentifier
+	Value asn1.RawValue
+}
+
+// nameHash is the hash of a distinguished name printed by openssl x509 -subject_hash, which
+// also names the certificates of a c_rehash directory. It is the little-endian first four
+// bytes of the SHA-1 of the canonical name, empty if the name can not be parsed.
+func nameHash(rawName []byte) string {
+	canonical, err := canonicalName(rawName)
+	if err != nil {
+		return ""
+	}
+	sum := sha1.Sum(canonical)
+	return fmt.Sprintf("%08x", binary.LittleEndian.Uint32(sum[:4]))
+}
+
+// canonicalName encodes the name like OpenSSL's x509_name_canon: text values become lowercase
+// UTF8Strings with collapsed whitespace and the RDN sets are concatenated without the outer
+// SEQUENCE.
+func canonicalName(rawName []byte) ([]byte, error) {
+	var rdns []asn1.RawValue
+	if rest, err := asn1.Unmarshal(rawName, &rdns); err != nil || len(rest) > 0 {
+		return nil, fmt.Errorf("invalid distinguished name")
+	}
+
+	var canonical []byte
+	for _, rdn := range rdns {
+		var attributes []rawAttribute
+		if _, err := asn1.UnmarshalWithParams(rdn.FullBytes, &attributes, "set"); err != nil {
+			return nil, err
+		}
+
+		encoded := make([][]byte, 0, len(attributes))
+		for _, attribute := range attributes {
+			if text, ok := nameText(attribute.Value); ok {
+				attribute.Value = asn1.RawValue{Tag: asn1.TagUTF8String, Bytes: []byte(canonicalText(text))}
+			}
+			der, err := asn1.Marshal(attribute)
+			if err != nil {
+				return nil, err
+			}
+			encoded = append(encoded, der)
+		}
+		sort.Slice(encoded, func(i, j int) bool { return bytes.Compare(encoded[i], encoded[j]) < 0 }) // DER SET OF
+
+		set, err := asn1.Marshal(asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: bytes.Join(encoded, nil)})
+		if err != nil {
+			return nil, err
+		}
+		canonical = append(canonical, set...)
+	}
+
+	return canonical, nil
+}
+
+const (
+	tagT61String       = 20
+	tagVisibleString   = 26
+	tagUniversalString = 28
+	tagBMPString       = 30
+)
+
+// nameText decodes the string types OpenSSL canonicalizes, other values are hashed as they are.
+func nameText(value asn1.RawValue) (string, bool) {
+	if value.Class != asn1.ClassUniversal {
+		return "", false
+	}
+
+	switch value.Tag {
+	case asn1.TagUTF8String, asn1.TagPrintableString, asn1.TagIA5String, tagVisibleString:
+		return string(value.Bytes), true
+	case tagT61String:
+		runes := make([]rune, len(value.Bytes)) // decoded as Latin-1 like OpenSSL
+		for i, b := range value.Bytes {
+			runes[i] = rune(b)
+		}
+		return string(runes), true
+	case tagBMPString:
+		units := make([]uint16, len(value.Bytes)/2)
+		for i := range units {
+			units[i] = binary.BigEndian.Uint16(value.Bytes[2*i:])
+		}
+		return string(utf16.Decode(units)), true
+	case tagUniversalString:
+		runes := make([]rune, len(value.Bytes)/4)
+		for i := range runes {
+			runes[i] = rune(binary.BigEndian.Uint32(value.Bytes[4*i:]))
+		}
+		return string(runes), true
+	}
+	return "", false
+}
+
+// canonicalText trims and collapses ASCII whitespace and lowercases ASCII letters, other
+// characters are kept.
+func canonicalText(text string) string {
+	var sb strings.Builder
+	space := false
+	for _, b := range []byte(strings.Trim(text, " \t\n\v\f\r")) {
+		switch {
+		case b == ' ' || b == '\t' || b == '\n' || b == '\v' || b == '\f' || b == '\r':
+			space = true
+			continue
+		case space:
+			sb.WriteByte(' ')
+			space = false
+		}
+		if 'A' <= b && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+		sb.WriteByte(b)
+	}
+	return sb.String()
+}
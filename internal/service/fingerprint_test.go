@@ -0,0 +1,67 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/repository"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+// The expected values are printed by openssl x509 -fingerprint, -subject_hash and -issuer_hash.
+func TestCertificateIdentifiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixture  string
+		expected service.CertificateIdentifiers
+	}{
+		{
+			name:    "Should match the openssl digests",
+			fixture: "tls.crt",
+			expected: service.CertificateIdentifiers{
+				SHA256Fingerprint: "A0:88:0A:35:A1:17:49:0F:17:3D:08:88:BC:56:1F:44:76:52:BF:D4:82:FB:9C:30:67:4A:25:6C:3E:9E:1E:F3",
+				SHA1Fingerprint:   "EE:38:16:6C:B4:AA:31:55:02:13:78:A3:11:8E:21:B7:EC:83:71:75",
+				SPKIPin:           "8D5BvVyb9gtZaiWFF6wyIRgNfkThV/NjISo5g2NIxCs=",
+				SubjectHash:       "ce275665",
+				IssuerHash:        "ce275665",
+			},
+		},
+		{
+			name:    "Should canonicalize multi-valued, mixed case and non-ASCII names like openssl",
+			fixture: "multi_rdn.crt",
+			expected: service.CertificateIdentifiers{
+				SubjectHash: "f5f07cd5",
+				IssuerHash:  "f5f07cd5",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cert, err := os.ReadFile(filepath.Join("..", "..", "test", tt.fixture))
+			if err != nil {
+				t.Fatalf("failed to read fixture: %v", err)
+			}
+
+			mockRepo := repository.NewMockRepository(nil, func(ctx context.Context, namespace, name string) (domains.SecretInfo, error) {
+				return domains.SecretInfo{Name: name, Namespace: namespace, TLSCert: cert}, nil
+			}, nil)
+
+			inspection, err := service.NewSecretsService(mockRepo).InspectTLSSecret(context.Background(), "default", "tls-secret")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := inspection.Certificates[0].CertificateIdentifiers
+			if tt.expected.SHA256Fingerprint == "" {
+				got.SHA256Fingerprint, got.SHA1Fingerprint, got.SPKIPin = "", "", ""
+			}
+			if got != tt.expected {
+				t.Errorf("expected identifiers %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
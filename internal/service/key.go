@@ -0,0 +1,267 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/codechamp1/certlens/internal/domains"
+)
+
+type KeyPairStatus int
+
+const (
+	KeyPairNotApplicable KeyPairStatus = iota
+	KeyPairMatch
+	KeyPairMismatch
+	KeyPairMissing
+	KeyPairInvalid
+	KeyPairUnknown
+)
+
+var keyPairStatusStrings = map[KeyPairStatus]string{
+	KeyPairNotApplicable: "N/A",
+	KeyPairMatch:         "Yes",
+	KeyPairMismatch:      "No",
+	KeyPairMissing:       "No Key",
+	KeyPairInvalid:       "Invalid Key",
+	KeyPairUnknown:       "Unknown",
+}
+
+func (k KeyPairStatus) String() string {
+	if str, ok := keyPairStatusStrings[k]; ok {
+		return str
+	}
+	return "Unknown"
+}
+
+// Mismatch reports whether the key was parsed and does not belong to the certificate.
+func (k KeyPairStatus) Mismatch() bool {
+	return k == KeyPairMismatch
+}
+
+type publicKeyEqualer interface {
+	Equal(x crypto.PublicKey) bool
+}
+
+// PrivateKeyInfo describes the private key of a secret, it never holds key material.
+type PrivateKeyInfo struct {
+	Type       string   `label:"Key Type" json:"type"`
+	Bits       int      `label:"Key Size (bits)" json:"bits"`
+	Curve      string   `label:"Curve" json:"curve,omitempty"`
+	Encoding   string   `label:"Encoding" json:"encoding"`
+	Encrypted  bool     `label:"Encrypted" json:"encrypted"`
+	Weaknesses []string `json:"weaknesses,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+const (
+	encodingPKCS1 = "PKCS#1"
+	encodingPKCS8 = "PKCS#8"
+	encodingSEC1  = "SEC1"
+)
+
+// minRSABits is the smallest RSA modulus not reported as weak, smaller keys are deprecated by NIST.
+const minRSABits = 2048
+
+// minRSAExponent is the smallest public exponent not reported as weak, e=3 allows low exponent attacks.
+const minRSAExponent = 65537
+
+var keyBlockEncodings = map[string]string{
+	"RSA PRIVATE KEY":       encodingPKCS1,
+	"EC PRIVATE KEY":        encodingSEC1,
+	"PRIVATE KEY":           encodingPKCS8,
+	"ENCRYPTED PRIVATE KEY": encodingPKCS8,
+}
+
+var keyBlockTypes = map[string]string{
+	"RSA PRIVATE KEY": "RSA",
+	"EC PRIVATE KEY":  "ECDSA",
+}
+
+func parsePrivateKey(pemData []byte) (crypto.PrivateKey, error) {
+	block, err := privateKeyBlock(pemData)
+	if err != nil {
+		return nil, err
+	}
+
+	if encryptedKeyBlock(block) {
+		return nil, fmt.Errorf("private key is encrypted")
+	}
+
+	key, _, err := parsePrivateKeyDER(block.Type, block.Bytes)
+	return key, err
+}
+
+// privateKeyBlock returns the first PEM block holding a private key.
+func privateKeyBlock(pemData []byte) (*pem.Block, error) {
+	data := pemData
+
+	for {
+		block, rest := pem.Decode(data)
+		if block == nil {
+			break
+		}
+		data = rest
+
+		if !strings.HasSuffix(block.Type, "PRIVATE KEY") {
+			continue // skip EC PARAMETERS, certificates etc.
+		}
+
+		return block, nil
+	}
+
+	return nil, fmt.Errorf("no private key found in input")
+}
+
+func encryptedKeyBlock(block *pem.Block) bool {
+	return block.Type == "ENCRYPTED PRIVATE KEY" || block.Headers["Proc-Type"] != ""
+}
+
+// parsePrivateKeyDER parses the key and returns the encoding it was stored in.
+func parsePrivateKeyDER(blockType string, der []byte) (crypto.PrivateKey, string, error) {
+	switch blockType {
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(der)
+		return key, encodingPKCS1, err
+	case "EC PRIVATE KEY":
+		key, err := x509.ParseECPrivateKey(der)
+		return key, encodingSEC1, err
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(der)
+		return key, encodingPKCS8, err
+	}
+
+	// unknown block type, try every supported encoding
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		return key, encodingPKCS8, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, encodingPKCS1, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, encodingSEC1, nil
+	}
+
+	return nil, "", fmt.Errorf("unsupported private key type %q", blockType)
+}
+
+// inspectPrivateKey describes the private key of a secret, it is nil if the secret holds no key.
+// Encrypted keys can not be parsed, only what their PEM block reveals is reported.
+func inspectPrivateKey(keyPEM []byte) *PrivateKeyInfo {
+	if len(keyPEM) == 0 {
+		return nil
+	}
+
+	block, err := privateKeyBlock(keyPEM)
+	if err != nil {
+		return &PrivateKeyInfo{Error: err.Error()}
+	}
+
+	info := &PrivateKeyInfo{Type: keyBlockTypes[block.Type], Encoding: keyBlockEncodings[block.Type]}
+	if encryptedKeyBlock(block) {
+		info.Encrypted = true
+		return info
+	}
+
+	key, encoding, err := parsePrivateKeyDER(block.Type, block.Bytes)
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	info.Encoding = encoding
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		info.Type = "RSA"
+		info.Bits = k.N.BitLen()
+		if info.Bits < minRSABits {
+			info.Weaknesses = append(info.Weaknesses, fmt.Sprintf("RSA key of %d bits is smaller than %d bits", info.Bits, minRSABits))
+		}
+		if k.E < minRSAExponent {
+			info.Weaknesses = append(info.Weaknesses, fmt.Sprintf("small RSA public exponent %d", k.E))
+		}
+	case *ecdsa.PrivateKey:
+		info.Type = "ECDSA"
+		info.Bits = k.Curve.Params().BitSize
+		info.Curve = k.Curve.Params().Name
+	case ed25519.PrivateKey:
+		info.Type = "Ed25519"
+		info.Bits = 256
+		info.Curve = "Ed25519"
+	default:
+		info.Type = fmt.Sprintf("%T", key)
+	}
+
+	return info
+}
+
+func publicKeyOf(key crypto.PrivateKey) (crypto.PublicKey, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k.Public(), nil
+	case *ecdsa.PrivateKey:
+		return k.Public(), nil
+	case ed25519.PrivateKey:
+		return k.Public(), nil
+	default:
+		return nil, fmt.Errorf("unsupported private key algorithm %T", key)
+	}
+}
+
+// publicKeyBits is the RSA modulus or curve size of a public key, 0 for unsupported algorithms.
+func publicKeyBits(pub crypto.PublicKey) int {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return k.N.BitLen()
+	case *ecdsa.PublicKey:
+		return k.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return 256
+	default:
+		return 0
+	}
+}
+
+// keyPairStatus checks the private key of the secret, resources found by the scanner and
+// local files often only hold certificates, such as CA bundles, so their key is only checked
+// if present. The caBundles of webhooks, CRDs and APIServices never hold a key and are always N/A.
+func keyPairStatus(secret domains.SecretInfo, leaf *x509.Certificate) KeyPairStatus {
+	keyOptional := secret.Kind != "" || secret.Type == domains.FileSecretType
+	if keyOptional && len(secret.TLSKey) == 0 {
+		return KeyPairNotApplicable
+	}
+	return checkKeyPair(leaf, secret.TLSKey)
+}
+
+func checkKeyPair(cert *x509.Certificate, keyPEM []byte) KeyPairStatus {
+	if len(keyPEM) == 0 {
+		return KeyPairMissing
+	}
+
+	key, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return KeyPairInvalid
+	}
+
+	pub, err := publicKeyOf(key)
+	if err != nil {
+		return KeyPairInvalid
+	}
+
+	certPub, ok := cert.PublicKey.(publicKeyEqualer)
+	if !ok {
+		return KeyPairUnknown
+	}
+
+	if certPub.Equal(pub) {
+		return KeyPairMatch
+	}
+
+	return KeyPairMismatch
+}
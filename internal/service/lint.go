@@ -0,0 +1,281 @@
+package service
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/codechamp1/certlens/internal/domains"
+)
+
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+var severityStrings = map[Severity]string{
+	SeverityInfo:    "info",
+	SeverityWarning: "warning",
+	SeverityError:   "error",
+}
+
+func (s Severity) String() string {
+	if str, ok := severityStrings[s]; ok {
+		return str
+	}
+	return "unknown"
+}
+
+// ParseSeverity parses the name of a severity, case-insensitively.
+func ParseSeverity(value string) (Severity, error) {
+	for severity, str := range severityStrings {
+		if strings.EqualFold(value, str) {
+			return severity, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown severity %q, supported severities: info, warning, error", value)
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Severity) UnmarshalText(text []byte) error {
+	severity, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = severity
+	return nil
+}
+
+// Finding is a lint rule violated by a certificate of a secret.
+type Finding struct {
+	ID       string   `json:"id"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	// Position of the certificate in the chain, 0 is the leaf.
+	Position int `json:"position"`
+}
+
+// LintSubject is a certificate checked by the lint rules.
+type LintSubject struct {
+	Certificate *x509.Certificate
+	// Info is the parsed form of Certificate.
+	Info CertificateInfo
+	// Resource and Labels identify the secret or scanned resource holding the certificate.
+	Resource domains.K8SResourceID
+	Labels   map[string]string
+	// Position of the certificate in the chain, 0 is the leaf.
+	Position int
+	// Leaf is set for the first certificate of TLS secrets, resources found by the scanner
+	// mostly hold CA bundles and are only checked by the rules for every certificate.
+	Leaf bool
+	// Public is set when the chain verifies against the system roots without the ca.crt of the
+	// secret, a private trust bundle does not make a certificate public.
+	Public bool
+}
+
+// LintRule checks a single certificate, Check returns why the certificate violates the rule
+// or an empty string.
+type LintRule struct {
+	ID          string
+	Severity    Severity
+	Description string
+	Check       func(subject LintSubject) string
+}
+
+// maxPublicLeafValidity is the longest validity of publicly trusted leaves accepted by the
+// CA/Browser Forum Baseline Requirements since September 2020.
+const maxPublicLeafValidity = 398 * 24 * time.Hour
+
+var oidBasicConstraints = asn1.ObjectIdentifier{2, 5, 29, 19}
+
+// DefaultLintRules are the rules checked unless the service is created WithLintRules.
+func DefaultLintRules() []LintRule {
+	return []LintRule{
+		{
+			ID:          "sha1-signature",
+			Severity:    SeverityError,
+			Description: "certificate is signed with SHA-1",
+			Check: func(s LintSubject) string {
+				if selfSignedRoot(s.Certificate) {
+					return "" // the signature of a trust anchor is never verified
+				}
+				switch s.Certificate.SignatureAlgorithm {
+				case x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1:
+					return fmt.Sprintf("signed with %s, SHA-1 signatures are rejected by browsers", s.Certificate.SignatureAlgorithm)
+				}
+				return ""
+			},
+		},
+		{
+			ID:          "public-leaf-validity",
+			Severity:    SeverityWarning,
+			Description: "publicly trusted leaf is valid for more than 398 days",
+			Check: func(s LintSubject) string {
+				validity := s.Certificate.NotAfter.Sub(s.Certificate.NotBefore)
+				if s.Leaf && s.Public && validity > maxPublicLeafValidity {
+					return fmt.Sprintf("valid for %d days, publicly trusted leaves may be valid for at most 398 days", int(validity.Hours()/24))
+				}
+				return ""
+			},
+		},
+		{
+			ID:          "missing-san",
+			Severity:    SeverityError,
+			Description: "leaf has no subject alternative names",
+			Check: func(s LintSubject) string {
+				cert := s.Certificate
+				if !s.Leaf || len(cert.DNSNames)+len(cert.IPAddresses)+len(cert.EmailAddresses)+len(cert.URIs) > 0 {
+					return ""
+				}
+				if cert.Subject.CommonName != "" {
+					return fmt.Sprintf("only the common name %q is set, clients ignore it for hostname verification", cert.Subject.CommonName)
+				}
+				return "no subject alternative names, the certificate matches no host"
+			},
+		},
+		{
+			ID:          "leaf-is-ca",
+			Severity:    SeverityWarning,
+			Description: "leaf is a CA certificate",
+			Check: func(s LintSubject) string {
+				if s.Leaf && s.Certificate.IsCA {
+					return "leaf has basic constraints CA:TRUE, it can issue certificates"
+				}
+				return ""
+			},
+		},
+		{
+			ID:          "missing-server-auth",
+			Severity:    SeverityWarning,
+			Description: "leaf has no serverAuth extended key usage",
+			Check: func(s LintSubject) string {
+				// without the extension every usage is allowed
+				if !s.Leaf || len(s.Certificate.ExtKeyUsage) == 0 {
+					return ""
+				}
+				for _, usage := range s.Certificate.ExtKeyUsage {
+					if usage == x509.ExtKeyUsageServerAuth || usage == x509.ExtKeyUsageAny {
+						return ""
+					}
+				}
+				return "extended key usage serverAuth is missing, TLS clients may reject the certificate"
+			},
+		},
+		{
+			ID:          "invalid-serial",
+			Severity:    SeverityError,
+			Description: "serial number is zero or negative",
+			Check: func(s LintSubject) string {
+				if serial := s.Certificate.SerialNumber; serial == nil || serial.Sign() <= 0 {
+					return fmt.Sprintf("serial number %v is not a positive integer as required by RFC 5280", serial)
+				}
+				return ""
+			},
+		},
+		{
+			ID:          "duplicate-san",
+			Severity:    SeverityWarning,
+			Description: "subject alternative names are listed more than once",
+			Check: func(s LintSubject) string {
+				if duplicates := duplicateSANs(s.Certificate); len(duplicates) > 0 {
+					return "duplicate subject alternative names: " + strings.Join(duplicates, ", ")
+				}
+				return ""
+			},
+		},
+		{
+			ID:          "basic-constraints-not-critical",
+			Severity:    SeverityWarning,
+			Description: "basic constraints of a CA certificate are not marked critical",
+			Check: func(s LintSubject) string {
+				if !s.Certificate.IsCA {
+					return ""
+				}
+				for _, ext := range s.Certificate.Extensions {
+					if ext.Id.Equal(oidBasicConstraints) && !ext.Critical {
+						return "basic constraints of a CA must be critical (RFC 5280 4.2.1.9)"
+					}
+				}
+				return ""
+			},
+		},
+	}
+}
+
+// WithLintRules replaces the DefaultLintRules checked for every inspected certificate.
+func WithLintRules(rules []LintRule) Option {
+	return func(s *secretsService) {
+		s.lintRules = rules
+	}
+}
+
+// lint checks every certificate of the chain against the rules.
+func lint(rules []LintRule, secret domains.SecretInfo, certs []*x509.Certificate, infos []CertificateInfo, public bool) []Finding {
+	var findings []Finding
+	for i, cert := range certs {
+		subject := LintSubject{
+			Certificate: cert,
+			Info:        infos[i],
+			Resource:    secret.ID(),
+			Labels:      secret.Labels,
+			Position:    i,
+			Leaf:        secret.Kind == "" && i == 0,
+			Public:      public,
+		}
+		for _, rule := range rules {
+			if message := rule.Check(subject); message != "" {
+				findings = append(findings, Finding{ID: rule.ID, Severity: rule.Severity, Message: message, Position: i})
+			}
+		}
+	}
+	return findings
+}
+
+// publiclyTrusted reports whether the chain verifies against the system roots alone. The chain
+// report is reused when it was verified against exactly those.
+func publiclyTrusted(certs []*x509.Certificate, caCerts []*x509.Certificate, chain ChainReport, trustBundle []*x509.Certificate, now time.Time) bool {
+	if len(caCerts) == 0 && trustBundle == nil {
+		return chain.Trusted
+	}
+	roots, err := rootsWithCA(nil, nil)
+	if err != nil {
+		return false
+	}
+	_, err = buildPaths(certs, roots, now)
+	return err == nil
+}
+
+func selfSignedRoot(cert *x509.Certificate) bool {
+	return cert.IsCA && cert.CheckSignatureFrom(cert) == nil
+}
+
+func duplicateSANs(cert *x509.Certificate) []string {
+	names := make([]string, 0, len(cert.DNSNames)+len(cert.IPAddresses)+len(cert.EmailAddresses)+len(cert.URIs))
+	for _, name := range cert.DNSNames {
+		names = append(names, strings.ToLower(name))
+	}
+	names = append(names, joinToStringSlice(cert.IPAddresses, func(ip net.IP) string { return ip.String() })...)
+	for _, email := range cert.EmailAddresses {
+		names = append(names, strings.ToLower(email))
+	}
+	names = append(names, joinToStringSlice(cert.URIs, func(uri *url.URL) string { return uri.String() })...)
+
+	seen := map[string]int{}
+	var duplicates []string
+	for _, name := range names {
+		if seen[name]++; seen[name] == 2 {
+			duplicates = append(duplicates, name)
+		}
+	}
+	return duplicates
+}
@@ -0,0 +1,237 @@
+package service_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/repository"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+func TestLintRules(t *testing.T) {
+	now := time.Now()
+	root := issueTestCertificate(t, "root", nil, true, now.Add(-time.Hour), now.Add(5*365*24*time.Hour))
+
+	leafTemplate := func() *x509.Certificate {
+		return &x509.Certificate{
+			SerialNumber: big.NewInt(42),
+			Subject:      pkix.Name{CommonName: "example.org"},
+			NotBefore:    now.Add(-time.Hour),
+			NotAfter:     now.Add(90 * 24 * time.Hour),
+			DNSNames:     []string{"example.org"},
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		template func() *x509.Certificate
+		signer   *testCA
+		sha1     bool
+		expected []string
+	}{
+		{
+			name:     "Should not report a well-formed leaf",
+			template: leafTemplate,
+			signer:   root,
+		},
+		{
+			name: "Should report a SHA-1 signature",
+			template: func() *x509.Certificate {
+				template := leafTemplate()
+				template.SignatureAlgorithm = x509.SHA1WithRSA
+				return template
+			},
+			sha1:     true,
+			expected: []string{"sha1-signature"},
+		},
+		{
+			name: "Should not treat a leaf trusted by a private trust bundle as public",
+			template: func() *x509.Certificate {
+				template := leafTemplate()
+				template.NotAfter = now.Add(400 * 24 * time.Hour)
+				return template
+			},
+			signer: root,
+		},
+		{
+			name: "Should report a leaf with only a common name",
+			template: func() *x509.Certificate {
+				template := leafTemplate()
+				template.DNSNames = nil
+				return template
+			},
+			signer:   root,
+			expected: []string{"missing-san"},
+		},
+		{
+			name: "Should report a CA leaf with non-critical basic constraints",
+			template: func() *x509.Certificate {
+				template := leafTemplate()
+				template.IsCA = true
+				template.BasicConstraintsValid = true
+				template.ExtraExtensions = []pkix.Extension{{Id: []int{2, 5, 29, 19}, Critical: false, Value: []byte{0x30, 0x03, 0x01, 0x01, 0xff}}}
+				return template
+			},
+			signer:   root,
+			expected: []string{"leaf-is-ca", "basic-constraints-not-critical"},
+		},
+		{
+			name: "Should report a missing serverAuth usage",
+			template: func() *x509.Certificate {
+				template := leafTemplate()
+				template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+				return template
+			},
+			signer:   root,
+			expected: []string{"missing-server-auth"},
+		},
+		{
+			name: "Should not report a leaf without extended key usage",
+			template: func() *x509.Certificate {
+				template := leafTemplate()
+				template.ExtKeyUsage = nil
+				return template
+			},
+			signer: root,
+		},
+		{
+			name: "Should report a zero serial number",
+			template: func() *x509.Certificate {
+				template := leafTemplate()
+				template.SerialNumber = big.NewInt(0)
+				return template
+			},
+			signer:   root,
+			expected: []string{"invalid-serial"},
+		},
+		{
+			name: "Should report duplicate subject alternative names",
+			template: func() *x509.Certificate {
+				template := leafTemplate()
+				template.DNSNames = []string{"example.org", "EXAMPLE.org"}
+				return template
+			},
+			signer:   root,
+			expected: []string{"duplicate-san"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var certPEM []byte
+			if tt.sha1 {
+				certPEM = signSHA1Certificate(t, tt.template())
+			} else {
+				certPEM = signTestCertificate(t, tt.template(), tt.signer)
+			}
+
+			mockRepo := repository.NewMockRepository(nil, func(ctx context.Context, namespace, name string) (domains.SecretInfo, error) {
+				return domains.SecretInfo{Name: name, Namespace: namespace, TLSCert: certPEM}, nil
+			}, nil)
+
+			svc := service.NewSecretsService(mockRepo, service.WithTrustBundle([]*x509.Certificate{root.cert}))
+			inspection, err := svc.InspectTLSSecret(context.Background(), "default", "tls-secret")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var ids []string
+			for _, finding := range inspection.Findings {
+				if finding.Position != 0 {
+					t.Errorf("expected findings of the leaf, got position %d", finding.Position)
+				}
+				ids = append(ids, finding.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.expected) {
+				t.Errorf("expected findings %v, got %v: %+v", tt.expected, ids, inspection.Findings)
+			}
+		})
+	}
+}
+
+func TestWithLintRules(t *testing.T) {
+	now := time.Now()
+	leaf := issueTestCertificate(t, "leaf", nil, false, now.Add(-time.Hour), now.Add(time.Hour))
+
+	mockRepo := repository.NewMockRepository(nil, func(ctx context.Context, namespace, name string) (domains.SecretInfo, error) {
+		return domains.SecretInfo{Name: name, Namespace: namespace, TLSCert: pemBundle(leaf)}, nil
+	}, nil)
+
+	rule := service.LintRule{
+		ID:       "short-lived",
+		Severity: service.SeverityInfo,
+		Check: func(s service.LintSubject) string {
+			if s.Leaf && s.Certificate.NotAfter.Sub(s.Certificate.NotBefore) < 24*time.Hour {
+				return "valid for less than a day"
+			}
+			return ""
+		},
+	}
+
+	svc := service.NewSecretsService(mockRepo, service.WithLintRules([]service.LintRule{rule}))
+	inspection, err := svc.InspectTLSSecret(context.Background(), "default", "tls-secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []service.Finding{{ID: "short-lived", Severity: service.SeverityInfo, Message: "valid for less than a day"}}
+	if !reflect.DeepEqual(inspection.Findings, expected) {
+		t.Errorf("expected only the findings of the custom rule %+v, got %+v", expected, inspection.Findings)
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	severity, err := service.ParseSeverity("Warning")
+	if err != nil || severity != service.SeverityWarning {
+		t.Errorf("expected warning, got %v (%v)", severity, err)
+	}
+
+	if _, err := service.ParseSeverity("fatal"); err == nil {
+		t.Error("expected an error for an unknown severity")
+	}
+}
+
+func signTestCertificate(t *testing.T, template *x509.Certificate, signer *testCA) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer.cert, &key.PublicKey, signer.key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+// signSHA1Certificate self-signs the template with an RSA key, ECDSA CAs can not sign with SHA1WithRSA.
+func signSHA1Certificate(t *testing.T, template *x509.Certificate) []byte {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
@@ -1,39 +1,69 @@
 package service
 
-import "github.com/codechamp1/certlens/internal/domains"
+import "context"
 
 type mockSecretService struct {
-	mockListTLSSecrets      func(namespace string) ([]domains.K8SResourceID, error)
-	mockListTLSSecret       func(namespace, name string) (domains.K8SResourceID, error)
-	mockInspectTLSSecret    func(namespace, name string) ([]CertificateInfo, error)
-	mockRawInspectTLSSecret func(namespace, name string) (string, string, error)
+	mockListTLSSecrets      func(ctx context.Context, namespace string) ([]TLSSecretSummary, error)
+	mockListTLSSecret       func(ctx context.Context, namespace, name string) (TLSSecretSummary, error)
+	mockInspectTLSSecret    func(ctx context.Context, namespace, name string) (TLSSecretInspection, error)
+	mockInspectTLSSecrets   func(ctx context.Context, namespace string) ([]TLSSecretInspection, error)
+	mockRawInspectTLSSecret func(ctx context.Context, namespace, name string) (string, string, error)
+	mockWatchTLSSecrets     func(ctx context.Context, namespace string) (<-chan TLSSecretEvent, error)
+	mockProbeTLSSecret      func(ctx context.Context, namespace, name, target string) (ProbeReport, error)
 }
 
 func NewMockSecretService(
-	mockListTLSSecrets func(namespace string) ([]domains.K8SResourceID, error),
-	mockListTLSSecret func(namespace, name string) (domains.K8SResourceID, error),
-	mockInspectTLSSecret func(namespace, name string) ([]CertificateInfo, error),
-	mockRawInspectTLSSecret func(namespace, name string) (string, string, error)) SecretsService {
+	mockListTLSSecrets func(ctx context.Context, namespace string) ([]TLSSecretSummary, error),
+	mockListTLSSecret func(ctx context.Context, namespace, name string) (TLSSecretSummary, error),
+	mockInspectTLSSecret func(ctx context.Context, namespace, name string) (TLSSecretInspection, error),
+	mockInspectTLSSecrets func(ctx context.Context, namespace string) ([]TLSSecretInspection, error),
+	mockRawInspectTLSSecret func(ctx context.Context, namespace, name string) (string, string, error),
+	mockWatchTLSSecrets func(ctx context.Context, namespace string) (<-chan TLSSecretEvent, error),
+	mockProbeTLSSecret func(ctx context.Context, namespace, name, target string) (ProbeReport, error)) SecretsService {
 	return mockSecretService{
 		mockInspectTLSSecret:    mockInspectTLSSecret,
+		mockInspectTLSSecrets:   mockInspectTLSSecrets,
 		mockListTLSSecret:       mockListTLSSecret,
 		mockListTLSSecrets:      mockListTLSSecrets,
 		mockRawInspectTLSSecret: mockRawInspectTLSSecret,
+		mockWatchTLSSecrets:     mockWatchTLSSecrets,
+		mockProbeTLSSecret:      mockProbeTLSSecret,
 	}
 }
 
-func (m mockSecretService) InspectTLSSecret(namespace, name string) ([]CertificateInfo, error) {
-	return m.mockInspectTLSSecret(namespace, name)
+func (m mockSecretService) InspectTLSSecret(ctx context.Context, namespace, name string) (TLSSecretInspection, error) {
+	return m.mockInspectTLSSecret(ctx, namespace, name)
 }
 
-func (m mockSecretService) ListTLSSecrets(namespace string) ([]domains.K8SResourceID, error) {
-	return m.mockListTLSSecrets(namespace)
+func (m mockSecretService) InspectTLSSecrets(ctx context.Context, namespace string) ([]TLSSecretInspection, error) {
+	return m.mockInspectTLSSecrets(ctx, namespace)
 }
 
-func (m mockSecretService) ListTLSSecret(namespace, name string) (domains.K8SResourceID, error) {
-	return m.mockListTLSSecret(namespace, name)
+func (m mockSecretService) ListTLSSecrets(ctx context.Context, namespace string) ([]TLSSecretSummary, error) {
+	return m.mockListTLSSecrets(ctx, namespace)
 }
 
-func (m mockSecretService) RawInspectTLSSecret(namespace, name string) (string, string, error) {
-	return m.mockRawInspectTLSSecret(namespace, name)
+// ListTLSSecretsPages returns the summaries of the ListTLSSecrets mock as a single page.
+func (m mockSecretService) ListTLSSecretsPages(ctx context.Context, namespace string, page func([]TLSSecretSummary) error) error {
+	summaries, err := m.mockListTLSSecrets(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return page(summaries)
+}
+
+func (m mockSecretService) ListTLSSecret(ctx context.Context, namespace, name string) (TLSSecretSummary, error) {
+	return m.mockListTLSSecret(ctx, namespace, name)
+}
+
+func (m mockSecretService) RawInspectTLSSecret(ctx context.Context, namespace, name string) (string, string, error) {
+	return m.mockRawInspectTLSSecret(ctx, namespace, name)
+}
+
+func (m mockSecretService) WatchTLSSecrets(ctx context.Context, namespace string) (<-chan TLSSecretEvent, error) {
+	return m.mockWatchTLSSecrets(ctx, namespace)
+}
+
+func (m mockSecretService) ProbeTLSSecret(ctx context.Context, namespace, name, target string) (ProbeReport, error) {
+	return m.mockProbeTLSSecret(ctx, namespace, name, target)
 }
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/codechamp1/certlens/internal/domains"
+)
+
+// Cluster is the SecretsService of a single kubeconfig context.
+type Cluster struct {
+	Name    string
+	Service SecretsService
+	// Err is why the context can not be inspected at all, e.g. its client can not be created.
+	// Every call then fails for the cluster and Service is not used.
+	Err error
+}
+
+// multiClusterService inspects several clusters as one inventory. Listings fan out to all
+// clusters concurrently and set the Cluster of every result. Namespaces qualified with a
+// cluster (see domains.QualifyNamespace) only address that cluster, an unqualified namespace
+// addresses all of them and single secrets are looked up in the clusters in order. Listings
+// keep the results of the reachable clusters when others fail, see domains.PartialError.
+type multiClusterService struct {
+	clusters []Cluster
+}
+
+func NewMultiClusterService(clusters ...Cluster) SecretsService {
+	return multiClusterService{
+		clusters: clusters,
+	}
+}
+
+func (m multiClusterService) InspectTLSSecret(ctx context.Context, namespace, name string) (TLSSecretInspection, error) {
+	return first(m, namespace, func(c Cluster, namespace string) (TLSSecretInspection, error) {
+		inspection, err := c.Service.InspectTLSSecret(ctx, namespace, name)
+		inspection.Cluster = c.Name
+		return inspection, err
+	})
+}
+
+func (m multiClusterService) InspectTLSSecrets(ctx context.Context, namespace string) ([]TLSSecretInspection, error) {
+	return fanOut(m, namespace, func(c Cluster, namespace string) ([]TLSSecretInspection, error) {
+		inspections, err := c.Service.InspectTLSSecrets(ctx, namespace)
+		for i := range inspections {
+			inspections[i].Cluster = c.Name
+		}
+		return inspections, err
+	})
+}
+
+func (m multiClusterService) ListTLSSecrets(ctx context.Context, namespace string) ([]TLSSecretSummary, error) {
+	return fanOut(m, namespace, func(c Cluster, namespace string) ([]TLSSecretSummary, error) {
+		summaries, err := c.Service.ListTLSSecrets(ctx, namespace)
+		for i := range summaries {
+			summaries[i].Cluster = c.Name
+		}
+		return summaries, err
+	})
+}
+
+// ListTLSSecretsPages streams the pages of all clusters concurrently, page is never called
+// concurrently. A cluster stops listing at the first error returned by page.
+func (m multiClusterService) ListTLSSecretsPages(ctx context.Context, namespace string, page func([]TLSSecretSummary) error) error {
+	var mu sync.Mutex
+	_, err := fanOut(m, namespace, func(c Cluster, namespace string) ([]TLSSecretSummary, error) {
+		return nil, c.Service.ListTLSSecretsPages(ctx, namespace, func(summaries []TLSSecretSummary) error {
+			for i := range summaries {
+				summaries[i].Cluster = c.Name
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			return page(summaries)
+		})
+	})
+	return err
+}
+
+func (m multiClusterService) ListTLSSecret(ctx context.Context, namespace, name string) (TLSSecretSummary, error) {
+	return first(m, namespace, func(c Cluster, namespace string) (TLSSecretSummary, error) {
+		summary, err := c.Service.ListTLSSecret(ctx, namespace, name)
+		summary.Cluster = c.Name
+		return summary, err
+	})
+}
+
+func (m multiClusterService) RawInspectTLSSecret(ctx context.Context, namespace, name string) (string, string, error) {
+	type raw struct{ cert, key string }
+	secret, err := first(m, namespace, func(c Cluster, namespace string) (raw, error) {
+		cert, key, err := c.Service.RawInspectTLSSecret(ctx, namespace, name)
+		return raw{cert, key}, err
+	})
+	return secret.cert, secret.key, err
+}
+
+func (m multiClusterService) ProbeTLSSecret(ctx context.Context, namespace, name, target string) (ProbeReport, error) {
+	return first(m, namespace, func(c Cluster, namespace string) (ProbeReport, error) {
+		return c.Service.ProbeTLSSecret(ctx, namespace, name, target)
+	})
+}
+
+// WatchTLSSecrets merges the events of all clusters, they are watched concurrently. It fails
+// only if every cluster fails, the clusters that can not be watched are a domains.PartialError.
+func (m multiClusterService) WatchTLSSecrets(ctx context.Context, namespace string) (<-chan TLSSecretEvent, error) {
+	type clusterEvents struct {
+		cluster string
+		events  <-chan TLSSecretEvent
+	}
+	watches, err := fanOut(m, namespace, func(c Cluster, namespace string) ([]clusterEvents, error) {
+		events, err := c.Service.WatchTLSSecrets(ctx, namespace)
+		if err != nil {
+			return nil, err
+		}
+		return []clusterEvents{{c.Name, events}}, nil
+	})
+	if err != nil && len(watches) == 0 {
+		return nil, err
+	}
+
+	events := make(chan TLSSecretEvent)
+	var wg sync.WaitGroup
+	for _, watch := range watches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for event := range watch.events {
+				event.Summary.Cluster = watch.cluster
+				select {
+				case events <- event:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(events)
+	}()
+	return events, err
+}
+
+// route selects the clusters addressed by a possibly qualified namespace.
+func (m multiClusterService) route(qualified string) ([]Cluster, string) {
+	name, namespace := domains.SplitNamespace(qualified)
+	if name == "" {
+		return m.clusters, namespace
+	}
+	for _, c := range m.clusters {
+		if c.Name == name {
+			return []Cluster{c}, namespace
+		}
+	}
+	return nil, namespace
+}
+
+// fanOut calls list for every addressed cluster concurrently, the results keep the cluster order.
+// It fails only if every cluster fails, otherwise the failed clusters and the failed sources of
+// the others are a domains.PartialError.
+func fanOut[T any](m multiClusterService, namespace string, list func(Cluster, string) ([]T, error)) ([]T, error) {
+	clusters, namespace := m.route(namespace)
+
+	results := make([][]T, len(clusters))
+	errs := make([]error, len(clusters))
+	var wg sync.WaitGroup
+	for i, c := range clusters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if c.Err != nil {
+				errs[i] = fmt.Errorf("cluster %s: %w", c.Name, c.Err)
+				return
+			}
+			results[i], errs[i] = list(c, namespace)
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("cluster %s: %w", c.Name, errs[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	var merged []T
+	var failed []error
+	var unreachable int
+	for i, result := range results {
+		var partial *domains.PartialError
+		switch {
+		case errors.As(errs[i], &partial):
+			// the cluster answered without some of its sources
+			for _, err := range partial.Errs {
+				failed = append(failed, fmt.Errorf("cluster %s: %w", clusters[i].Name, err))
+			}
+		case errs[i] != nil:
+			failed = append(failed, errs[i])
+			unreachable++
+			continue
+		}
+		merged = append(merged, result...)
+	}
+
+	switch {
+	case len(failed) == 0:
+		return merged, nil
+	case unreachable == len(clusters):
+		return nil, errors.Join(failed...)
+	default:
+		return merged, &domains.PartialError{Errs: failed}
+	}
+}
+
+// first returns the result of the first addressed cluster that get succeeds for.
+func first[T any](m multiClusterService, namespace string, get func(Cluster, string) (T, error)) (T, error) {
+	clusters, clusterNamespace := m.route(namespace)
+
+	var errs []error
+	for _, c := range clusters {
+		if c.Err != nil {
+			errs = append(errs, fmt.Errorf("cluster %s: %w", c.Name, c.Err))
+			continue
+		}
+		result, err := get(c, clusterNamespace)
+		if err == nil {
+			return result, nil
+		}
+		errs = append(errs, fmt.Errorf("cluster %s: %w", c.Name, err))
+	}
+
+	var zero T
+	if len(errs) == 0 {
+		return zero, fmt.Errorf("no cluster matches namespace %s", namespace)
+	}
+	return zero, errors.Join(errs...)
+}
@@ -0,0 +1,227 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+func clusterService(name string, err error, calls *[]string) service.SecretsService {
+	return service.NewMockSecretService(
+		func(ctx context.Context, namespace string) ([]service.TLSSecretSummary, error) {
+			*calls = append(*calls, name+":"+namespace)
+			return []service.TLSSecretSummary{{K8SResourceID: domains.K8SResourceID{Name: "web-tls", Namespace: "default"}}}, err
+		},
+		nil,
+		func(ctx context.Context, namespace, secret string) (service.TLSSecretInspection, error) {
+			*calls = append(*calls, name+":"+namespace)
+			if err != nil || secret != name+"-tls" {
+				return service.TLSSecretInspection{}, errors.Join(err, errRepo)
+			}
+			return service.TLSSecretInspection{K8SResourceID: domains.K8SResourceID{Name: secret, Namespace: namespace}}, nil
+		},
+		nil, nil,
+		func(ctx context.Context, namespace string) (<-chan service.TLSSecretEvent, error) {
+			if err != nil {
+				return nil, err
+			}
+			events := make(chan service.TLSSecretEvent, 1)
+			events <- service.TLSSecretEvent{Type: domains.SecretAdded, Summary: service.TLSSecretSummary{K8SResourceID: domains.K8SResourceID{Name: "web-tls"}}}
+			close(events)
+			return events, nil
+		},
+		nil,
+	)
+}
+
+func TestMultiClusterService(t *testing.T) {
+	t.Run("Should list all clusters and set the cluster of every summary", func(t *testing.T) {
+		var callsA, callsB []string
+		svc := service.NewMultiClusterService(
+			service.Cluster{Name: "prod", Service: clusterService("prod", nil, &callsA)},
+			service.Cluster{Name: "arn:aws:eks:eu-west-1:123:cluster/staging", Service: clusterService("staging", nil, &callsB)},
+		)
+
+		summaries, err := svc.ListTLSSecrets(context.Background(), "default")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(summaries) != 2 || summaries[0].Cluster != "prod" || summaries[1].Cluster != "arn:aws:eks:eu-west-1:123:cluster/staging" {
+			t.Errorf("expected one summary per cluster in order, got %+v", summaries)
+		}
+		if summaries[1].QualifiedNamespace() != "arn:aws:eks:eu-west-1:123:cluster/staging/default" {
+			t.Errorf("unexpected qualified namespace %s", summaries[1].QualifiedNamespace())
+		}
+
+		callsA, callsB = nil, nil
+		if _, err := svc.ListTLSSecrets(context.Background(), summaries[1].QualifiedNamespace()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(callsA) != 0 || len(callsB) != 1 || callsB[0] != "staging:default" {
+			t.Errorf("expected only staging to be listed in default, got %v and %v", callsA, callsB)
+		}
+	})
+
+	t.Run("Should stream the pages of all clusters with their cluster set", func(t *testing.T) {
+		var callsA, callsB []string
+		svc := service.NewMultiClusterService(
+			service.Cluster{Name: "prod", Service: clusterService("prod", nil, &callsA)},
+			service.Cluster{Name: "staging", Service: clusterService("staging", nil, &callsB)},
+		)
+
+		clusters := map[string]int{}
+		err := svc.ListTLSSecretsPages(context.Background(), "default", func(page []service.TLSSecretSummary) error {
+			for _, summary := range page {
+				clusters[summary.Cluster]++
+			}
+			return nil
+		})
+		if err != nil || clusters["prod"] != 1 || clusters["staging"] != 1 {
+			t.Errorf("expected a summary of each cluster, got %v, %v", clusters, err)
+		}
+	})
+
+	t.Run("Should look up single secrets in the clusters in order", func(t *testing.T) {
+		var calls []string
+		svc := service.NewMultiClusterService(
+			service.Cluster{Name: "prod", Service: clusterService("prod", nil, &calls)},
+			service.Cluster{Name: "staging", Service: clusterService("staging", nil, &calls)},
+		)
+
+		inspection, err := svc.InspectTLSSecret(context.Background(), "default", "staging-tls")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if inspection.Cluster != "staging" || len(calls) != 2 {
+			t.Errorf("expected the secret of staging after trying prod, got %+v after %v", inspection, calls)
+		}
+
+		if _, err := svc.InspectTLSSecret(context.Background(), "unknown/default", "staging-tls"); err == nil {
+			t.Error("expected error for an unknown cluster, got nil")
+		}
+	})
+
+	t.Run("Should watch the clusters that can be watched", func(t *testing.T) {
+		var callsA, callsB []string
+		svc := service.NewMultiClusterService(
+			service.Cluster{Name: "prod", Service: clusterService("prod", nil, &callsA)},
+			service.Cluster{Name: "staging", Service: clusterService("staging", errRepo, &callsB)},
+		)
+
+		events, err := svc.WatchTLSSecrets(context.Background(), "")
+		var partial *domains.PartialError
+		if !errors.As(err, &partial) || !errors.Is(err, errRepo) {
+			t.Errorf("expected a partial error of staging, got %v", err)
+		}
+
+		var clusters []string
+		for event := range events {
+			clusters = append(clusters, event.Summary.Cluster)
+		}
+		if len(clusters) != 1 || clusters[0] != "prod" {
+			t.Errorf("expected the events of prod, got %v", clusters)
+		}
+	})
+
+	t.Run("Should fail the watch if no cluster can be watched", func(t *testing.T) {
+		var calls []string
+		svc := service.NewMultiClusterService(service.Cluster{Name: "staging", Service: clusterService("staging", errRepo, &calls)})
+
+		if events, err := svc.WatchTLSSecrets(context.Background(), ""); err == nil || events != nil {
+			t.Errorf("expected an error, got %v", err)
+		}
+	})
+
+	t.Run("Should report clusters that can not be opened as failed", func(t *testing.T) {
+		var calls []string
+		svc := service.NewMultiClusterService(
+			service.Cluster{Name: "prod", Service: clusterService("prod", nil, &calls)},
+			service.Cluster{Name: "broken", Err: errRepo},
+		)
+
+		summaries, err := svc.ListTLSSecrets(context.Background(), "")
+		var partial *domains.PartialError
+		if !errors.As(err, &partial) || !errors.Is(err, errRepo) || len(summaries) != 1 {
+			t.Errorf("expected the summary of prod and a partial error of broken, got %+v, %v", summaries, err)
+		}
+
+		if _, err := svc.InspectTLSSecret(context.Background(), "broken/default", "broken-tls"); !errors.Is(err, errRepo) {
+			t.Errorf("expected the error of broken, got %v", err)
+		}
+	})
+
+	t.Run("Should keep the reachable clusters if a cluster can not be listed", func(t *testing.T) {
+		var callsA, callsB []string
+		svc := service.NewMultiClusterService(
+			service.Cluster{Name: "prod", Service: clusterService("prod", nil, &callsA)},
+			service.Cluster{Name: "staging", Service: clusterService("staging", errRepo, &callsB)},
+		)
+
+		summaries, err := svc.ListTLSSecrets(context.Background(), "")
+		var partial *domains.PartialError
+		if !errors.As(err, &partial) || !errors.Is(err, errRepo) || len(partial.Errs) != 1 {
+			t.Errorf("expected a partial error of staging, got %v", err)
+		}
+		if len(summaries) != 1 || summaries[0].Cluster != "prod" {
+			t.Errorf("expected the summary of prod, got %+v", summaries)
+		}
+	})
+
+	t.Run("Should keep the summaries of a cluster with failed sources", func(t *testing.T) {
+		var callsA, callsB []string
+		sourceErr := &domains.PartialError{Errs: []error{errRepo}}
+		svc := service.NewMultiClusterService(
+			service.Cluster{Name: "prod", Service: clusterService("prod", sourceErr, &callsA)},
+			service.Cluster{Name: "staging", Service: clusterService("staging", sourceErr, &callsB)},
+		)
+
+		summaries, err := svc.ListTLSSecrets(context.Background(), "")
+		var partial *domains.PartialError
+		if !errors.As(err, &partial) || len(partial.Errs) != 2 || partial.Errs[1].Error() != "cluster staging: "+errRepo.Error() {
+			t.Errorf("expected a partial error per cluster, got %v", err)
+		}
+		if len(summaries) != 2 {
+			t.Errorf("expected the summaries of both clusters, got %+v", summaries)
+		}
+	})
+
+	t.Run("Should fail if no cluster can be listed", func(t *testing.T) {
+		var callsA, callsB []string
+		svc := service.NewMultiClusterService(
+			service.Cluster{Name: "prod", Service: clusterService("prod", errRepo, &callsA)},
+			service.Cluster{Name: "staging", Service: clusterService("staging", errRepo, &callsB)},
+		)
+
+		summaries, err := svc.ListTLSSecrets(context.Background(), "")
+		var partial *domains.PartialError
+		if !errors.Is(err, errRepo) || errors.As(err, &partial) || summaries != nil {
+			t.Errorf("expected error %v without summaries, got %v and %+v", errRepo, err, summaries)
+		}
+	})
+
+	t.Run("Should merge the events of all clusters", func(t *testing.T) {
+		var calls []string
+		svc := service.NewMultiClusterService(
+			service.Cluster{Name: "prod", Service: clusterService("prod", nil, &calls)},
+			service.Cluster{Name: "staging", Service: clusterService("staging", nil, &calls)},
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		events, err := svc.WatchTLSSecrets(ctx, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		clusters := map[string]bool{}
+		for event := range events {
+			clusters[event.Summary.Cluster] = true
+		}
+		if !clusters["prod"] || !clusters["staging"] {
+			t.Errorf("expected events of both clusters, got %v", clusters)
+		}
+	})
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PolicyRule is an organisation rule, it selects secrets by namespace, name and labels and
+// requires predicates over the fields of their certificates.
+type PolicyRule struct {
+	ID          string
+	Description string
+	Severity    Severity
+	Match       PolicySelector
+	Require     []PolicyPredicate
+}
+
+// PolicySelector selects the certificates a rule applies to, empty lists select everything.
+// Namespaces and names are glob patterns, labels must all be equal. Certificates is
+// PolicyLeaf (the default) or PolicyAll.
+type PolicySelector struct {
+	Namespaces   []string
+	Names        []string
+	Labels       map[string]string
+	Certificates string
+}
+
+// PolicyPredicate constrains a CertificateInfo field by its JSON name. Every element of list
+// fields must satisfy Equals, OneOf and Matches, Min and Max bound numbers and durations.
+type PolicyPredicate struct {
+	Field   string
+	Equals  *string
+	OneOf   []string
+	Matches string
+	Min     *PolicyBound
+	Max     *PolicyBound
+}
+
+// PolicyBound is a Min or Max as written in the policy, e.g. 90d. Number and Duration are
+// set if Text reads as one, the type of the field decides which one applies.
+type PolicyBound struct {
+	Text     string
+	Number   *float64
+	Duration *time.Duration
+}
+
+const (
+	PolicyLeaf = "leaf"
+	PolicyAll  = "all"
+)
+
+// CompilePolicy compiles the rules of a policy, they are checked alongside the
+// DefaultLintRules and reported as findings.
+func CompilePolicy(policy []PolicyRule) ([]LintRule, error) {
+	rules := make([]LintRule, 0, len(policy))
+	for i, rule := range policy {
+		compiled, err := rule.compile()
+		if err != nil {
+			return nil, fmt.Errorf("rule %d (%s): %w", i+1, rule.ID, err)
+		}
+		rules = append(rules, compiled)
+	}
+
+	return rules, nil
+}
+
+func (r PolicyRule) compile() (LintRule, error) {
+	if r.ID == "" {
+		return LintRule{}, fmt.Errorf("id is required")
+	}
+	if len(r.Require) == 0 {
+		return LintRule{}, fmt.Errorf("require lists no predicates")
+	}
+
+	if err := r.Match.validate(); err != nil {
+		return LintRule{}, err
+	}
+
+	checks := make([]func(CertificateInfo) string, 0, len(r.Require))
+	for _, predicate := range r.Require {
+		check, err := predicate.compile()
+		if err != nil {
+			return LintRule{}, fmt.Errorf("field %s: %w", predicate.Field, err)
+		}
+		checks = append(checks, check)
+	}
+
+	return LintRule{
+		ID:          r.ID,
+		Severity:    r.Severity,
+		Description: r.Description,
+		Check: func(s LintSubject) string {
+			if !r.Match.selects(s) {
+				return ""
+			}
+
+			var violations []string
+			for _, check := range checks {
+				if violation := check(s.Info); violation != "" {
+					violations = append(violations, violation)
+				}
+			}
+			if len(violations) == 0 {
+				return ""
+			}
+
+			message := strings.Join(violations, "; ")
+			if r.Description != "" {
+				message = r.Description + ": " + message
+			}
+			return message
+		},
+	}, nil
+}
+
+func (s PolicySelector) validate() error {
+	if s.Certificates != "" && s.Certificates != PolicyLeaf && s.Certificates != PolicyAll {
+		return fmt.Errorf("certificates must be %s or %s, got %q", PolicyLeaf, PolicyAll, s.Certificates)
+	}
+	for _, pattern := range slices.Concat(s.Namespaces, s.Names) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+func (s PolicySelector) selects(subject LintSubject) bool {
+	if s.Certificates != PolicyAll && subject.Position != 0 {
+		return false
+	}
+	if !matchesAny(s.Namespaces, subject.Resource.Namespace) || !matchesAny(s.Names, subject.Resource.Name) {
+		return false
+	}
+	for key, value := range s.Labels {
+		if label, ok := subject.Labels[key]; !ok || label != value {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// certificateFields indexes the fields of CertificateInfo by their JSON name.
+var certificateFields = func() map[string][]int {
+	fields := map[string][]int{}
+	t := reflect.TypeOf(CertificateInfo{})
+	for i := 0; i < t.NumField(); i++ {
+		section := t.Field(i).Type
+		for j := 0; j < section.NumField(); j++ {
+			name, _, _ := strings.Cut(section.Field(j).Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			fields[name] = []int{i, j}
+		}
+	}
+	return fields
+}()
+
+var durationType = reflect.TypeOf(Duration(0))
+
+func (p PolicyPredicate) compile() (func(CertificateInfo) string, error) {
+	index, ok := certificateFields[p.Field]
+	if !ok {
+		return nil, fmt.Errorf("unknown certificate field")
+	}
+	fieldType := reflect.TypeOf(CertificateInfo{}).FieldByIndex(index).Type
+
+	var pattern *regexp.Regexp
+	if p.Matches != "" {
+		var err error
+		if pattern, err = regexp.Compile(p.Matches); err != nil {
+			return nil, fmt.Errorf("invalid matches pattern: %w", err)
+		}
+	}
+
+	var checks []func(reflect.Value) string
+	if p.Equals != nil || p.OneOf != nil || pattern != nil {
+		if fieldType.Kind() != reflect.String && fieldType.Kind() != reflect.Slice && fieldType.Kind() != reflect.Bool {
+			return nil, fmt.Errorf("equals, oneOf and matches only apply to text, lists and booleans")
+		}
+		checks = append(checks, func(v reflect.Value) string { return p.checkText(v, pattern) })
+	}
+
+	for _, bound := range []struct {
+		value *PolicyBound
+		upper bool
+	}{{p.Min, false}, {p.Max, true}} {
+		if bound.value == nil {
+			continue
+		}
+		limit, err := bound.value.limit(fieldType)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, boundCheck(p.Field, fieldType, limit, bound.value.Text, bound.upper))
+	}
+
+	if len(checks) == 0 {
+		return nil, fmt.Errorf("no constraint, set equals, oneOf, matches, min or max")
+	}
+
+	return func(info CertificateInfo) string {
+		value := reflect.ValueOf(info).FieldByIndex(index)
+		var violations []string
+		for _, check := range checks {
+			if violation := check(value); violation != "" {
+				violations = append(violations, violation)
+			}
+		}
+		return strings.Join(violations, "; ")
+	}, nil
+}
+
+// checkText applies equals, oneOf and matches to a text or boolean field, or to every element of a list.
+func (p PolicyPredicate) checkText(value reflect.Value, pattern *regexp.Regexp) string {
+	var values []string
+	switch value.Kind() {
+	case reflect.Slice:
+		values = value.Interface().([]string)
+	default:
+		values = []string{fmt.Sprint(value.Interface())}
+	}
+
+	for _, v := range values {
+		switch {
+		case p.Equals != nil && v != *p.Equals:
+			return fmt.Sprintf("%s %q is not %q", p.Field, v, *p.Equals)
+		case p.OneOf != nil && !slices.Contains(p.OneOf, v):
+			return fmt.Sprintf("%s %q is not one of %s", p.Field, v, strings.Join(p.OneOf, ", "))
+		case pattern != nil && !pattern.MatchString(v):
+			return fmt.Sprintf("%s %q does not match %q", p.Field, v, pattern)
+		}
+	}
+	return ""
+}
+
+// limit returns the bound as a duration (e.g. 90d, 2160h) for durations and as a number otherwise.
+func (b PolicyBound) limit(fieldType reflect.Type) (float64, error) {
+	switch {
+	case fieldType == durationType:
+		if b.Duration == nil {
+			return 0, fmt.Errorf("invalid duration %q", b.Text)
+		}
+		return float64(*b.Duration), nil
+	case fieldType.Kind() == reflect.Int || fieldType.Kind() == reflect.Float64:
+		if b.Number == nil {
+			return 0, fmt.Errorf("invalid number %q", b.Text)
+		}
+		return *b.Number, nil
+	default:
+		return 0, fmt.Errorf("min and max only apply to numbers and durations")
+	}
+}
+
+func boundCheck(field string, fieldType reflect.Type, limit float64, bound string, upper bool) func(reflect.Value) string {
+	return func(value reflect.Value) string {
+		var n float64
+		var shown string
+		switch {
+		case fieldType == durationType:
+			d := time.Duration(value.Int())
+			n, shown = float64(d), d.String()
+		case fieldType.Kind() == reflect.Int:
+			n = float64(value.Int())
+			shown = strconv.FormatInt(value.Int(), 10)
+		default:
+			n = value.Float()
+			shown = fmt.Sprintf("%.2f", n)
+		}
+
+		switch {
+		case upper && n > limit:
+			return fmt.Sprintf("%s %s is above the maximum %s", field, shown, bound)
+		case !upper && n < limit:
+			return fmt.Sprintf("%s %s is below the minimum %s", field, shown, bound)
+		}
+		return ""
+	}
+}
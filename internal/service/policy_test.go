@@ -0,0 +1,122 @@
+package service_test
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/repository"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+func testPolicy() []service.PolicyRule {
+	maxValidity := 90 * 24 * time.Hour
+	minBits := 256.0
+
+	return []service.PolicyRule{
+		{
+			ID:          "internal-issuer",
+			Description: "prod certificates are issued by the internal CA",
+			Severity:    service.SeverityError,
+			Match:       service.PolicySelector{Namespaces: []string{"prod-*"}},
+			Require:     []service.PolicyPredicate{{Field: "issuer", Matches: "CN=internal-ca"}},
+		},
+		{
+			ID:       "max-validity",
+			Severity: service.SeverityWarning,
+			Match:    service.PolicySelector{Labels: map[string]string{"team": "payments"}},
+			Require:  []service.PolicyPredicate{{Field: "totalValidity", Max: &service.PolicyBound{Text: "90d", Duration: &maxValidity}}},
+		},
+		{
+			ID:       "ecdsa-p256",
+			Severity: service.SeverityError,
+			Require: []service.PolicyPredicate{
+				{Field: "publicKeyAlgorithm", OneOf: []string{"ECDSA"}},
+				{Field: "publicKeyBits", Min: &service.PolicyBound{Text: "256", Number: &minBits}},
+			},
+		},
+		{
+			ID:       "corp-sans",
+			Severity: service.SeverityError,
+			Match:    service.PolicySelector{Names: []string{"web-*"}},
+			Require:  []service.PolicyPredicate{{Field: "dnsNames", Matches: `\.corp\.example$`}},
+		},
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	now := time.Now()
+	root := issueTestCertificate(t, "public-ca", nil, true, now.Add(-time.Hour), now.Add(365*24*time.Hour))
+
+	leaf := signTestCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(7),
+		Subject:      pkix.Name{CommonName: "shop.example.org"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(180 * 24 * time.Hour),
+		DNSNames:     []string{"shop.corp.example", "shop.example.org"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, root)
+
+	rules, err := service.CompilePolicy(testPolicy())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		namespace string
+		secret    string
+		labels    map[string]string
+		expected  []service.Finding
+	}{
+		{
+			name:      "Should not report certificates outside the selectors",
+			namespace: "default",
+			secret:    "api",
+		},
+		{
+			name:      "Should report a violation in a selected namespace",
+			namespace: "prod-eu",
+			secret:    "api",
+			expected: []service.Finding{{ID: "internal-issuer", Severity: service.SeverityError,
+				Message: `prod certificates are issued by the internal CA: issuer "CN=public-ca" does not match "CN=internal-ca"`}},
+		},
+		{
+			name:      "Should report a violation of a labelled secret",
+			namespace: "default",
+			secret:    "api",
+			labels:    map[string]string{"team": "payments"},
+			expected: []service.Finding{{ID: "max-validity", Severity: service.SeverityWarning,
+				Message: "totalValidity 4321h0m0s is above the maximum 90d"}},
+		},
+		{
+			name:      "Should report every list element violating a selected name",
+			namespace: "default",
+			secret:    "web-shop",
+			expected: []service.Finding{{ID: "corp-sans", Severity: service.SeverityError,
+				Message: `dnsNames "shop.example.org" does not match "\\.corp\\.example$"`}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockRepository(nil, func(ctx context.Context, namespace, name string) (domains.SecretInfo, error) {
+				return domains.SecretInfo{Name: name, Namespace: namespace, TLSCert: leaf, Labels: tt.labels}, nil
+			}, nil)
+
+			svc := service.NewSecretsService(mockRepo, service.WithLintRules(rules))
+			inspection, err := svc.InspectTLSSecret(context.Background(), tt.namespace, tt.secret)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(inspection.Findings, tt.expected) {
+				t.Errorf("expected findings %+v, got %+v", tt.expected, inspection.Findings)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/codechamp1/certlens/internal/repository"
+)
+
+// Results of comparing a served chain with the stored one.
+const (
+	ProbeNotCompared   = "Not compared"
+	ProbeMatch         = "Match"
+	ProbeChainMismatch = "Chain differs"
+	ProbeLeafMismatch  = "Serving a different certificate"
+)
+
+var errNoProber = errors.New("probing endpoints is not configured")
+
+// ProbeReport holds the chain presented by a live TLS endpoint and how it compares to a secret.
+type ProbeReport struct {
+	Target       string            `json:"target"`
+	Status       string            `json:"status"`
+	Certificates []CertificateInfo `json:"certificates"`
+	// Fingerprints pairs the served and stored certificates by chain position.
+	Fingerprints []FingerprintDiff `json:"fingerprints,omitempty"`
+}
+
+// FingerprintDiff compares the SHA-256 fingerprints of the certificates at one chain position,
+// a fingerprint is empty if the chain has no certificate at that position.
+type FingerprintDiff struct {
+	Position int    `json:"position"`
+	Served   string `json:"served"`
+	Stored   string `json:"stored"`
+}
+
+func (d FingerprintDiff) Match() bool {
+	return d.Served == d.Stored
+}
+
+// Fingerprints returns the served and stored fingerprint for display, "-" for a missing certificate.
+func (d FingerprintDiff) Fingerprints() (served, stored string) {
+	orNone := func(fingerprint string) string {
+		if fingerprint == "" {
+			return "-"
+		}
+		return fingerprint
+	}
+	return orNone(d.Served), orNone(d.Stored)
+}
+
+// Stale reports whether the endpoint serves another leaf than the secret, e.g. because the
+// secret was rotated but the pod was not restarted.
+func (r ProbeReport) Stale() bool {
+	return r.Status == ProbeLeafMismatch
+}
+
+// WithProber enables probing live TLS endpoints.
+func WithProber(repo repository.ProbeRepository) Option {
+	return func(s *secretsService) {
+		s.prober = repo
+	}
+}
+
+// ProbeTLSSecret captures the chain served by target and compares it to the secret, the
+// comparison is skipped if name is empty. See repository.ProbeRepository for the targets.
+func (s secretsService) ProbeTLSSecret(ctx context.Context, namespace, name, target string) (ProbeReport, error) {
+	if s.prober == nil {
+		return ProbeReport{}, errNoProber
+	}
+
+	data, err := s.prober.ProbeEndpoint(ctx, namespace, target)
+	if err != nil {
+		return ProbeReport{}, fmt.Errorf("can not probe %s: %w", target, err)
+	}
+	served, err := parseCertsFromString(string(data))
+	if err != nil {
+		return ProbeReport{}, fmt.Errorf("can not parse the chain served by %s: %w", target, err)
+	}
+
+	report := ProbeReport{
+		Target:       target,
+		Status:       ProbeNotCompared,
+		Certificates: parseCertificates(served, s.expiry.For(namespace)),
+	}
+	if name == "" {
+		return report, nil
+	}
+
+	secret, err := s.GetTLSSecret(ctx, namespace, name)
+	if err != nil {
+		return ProbeReport{}, fmt.Errorf("can not compare with TLS secret: %w", err)
+	}
+	stored, err := parseCertsFromString(string(secret.TLSCert))
+	if err != nil {
+		return ProbeReport{}, fmt.Errorf("can not compare with TLS secret: %w", err)
+	}
+
+	report.Fingerprints = diffFingerprints(served, stored)
+	report.Status = ProbeMatch
+	for _, diff := range report.Fingerprints {
+		if !diff.Match() {
+			report.Status = ProbeChainMismatch
+		}
+	}
+	if !report.Fingerprints[0].Match() {
+		report.Status = ProbeLeafMismatch
+	}
+	return report, nil
+}
+
+func diffFingerprints(served, stored []*x509.Certificate) []FingerprintDiff {
+	diffs := make([]FingerprintDiff, max(len(served), len(stored)))
+	for i := range diffs {
+		diffs[i].Position = i
+		if i < len(served) {
+			diffs[i].Served = sha256Fingerprint(served[i])
+		}
+		if i < len(stored) {
+			diffs[i].Stored = sha256Fingerprint(stored[i])
+		}
+	}
+	return diffs
+}
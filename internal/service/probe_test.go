@@ -0,0 +1,75 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/repository"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+func TestProbeTLSSecret(t *testing.T) {
+	now := time.Now()
+	root := issueTestCertificate(t, "root", nil, true, now.Add(-time.Hour), now.Add(48*time.Hour))
+	intermediate := issueTestCertificate(t, "intermediate", root, true, now.Add(-time.Hour), now.Add(48*time.Hour))
+	leaf := issueTestCertificate(t, "example.com", intermediate, false, now.Add(-time.Hour), now.Add(24*time.Hour))
+	rotated := issueTestCertificate(t, "example.com", intermediate, false, now, now.Add(24*time.Hour))
+
+	secretRepo := repository.NewMockRepository(nil, func(ctx context.Context, namespace, name string) (domains.SecretInfo, error) {
+		return domains.SecretInfo{Name: name, Namespace: namespace, TLSCert: pemBundle(leaf, intermediate)}, nil
+	}, nil)
+
+	tests := []struct {
+		name           string
+		secretName     string
+		served         []byte
+		probeErr       error
+		expectedStatus string
+		expectedDiffs  int
+		expectedErr    bool
+	}{
+		{name: "Should match an endpoint serving the secret", secretName: "web-tls", served: pemBundle(leaf, intermediate), expectedStatus: service.ProbeMatch, expectedDiffs: 2},
+		{name: "Should detect an endpoint still serving the old certificate", secretName: "web-tls", served: pemBundle(rotated, intermediate), expectedStatus: service.ProbeLeafMismatch, expectedDiffs: 2},
+		{name: "Should detect a served chain missing the intermediate", secretName: "web-tls", served: pemBundle(leaf), expectedStatus: service.ProbeChainMismatch, expectedDiffs: 2},
+		{name: "Should only capture the chain without a secret", served: pemBundle(leaf), expectedStatus: service.ProbeNotCompared},
+		{name: "Should return error if the endpoint can not be probed", secretName: "web-tls", probeErr: errRepo, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewSecretsService(secretRepo, service.WithProber(repository.NewMockProbeRepository(func(ctx context.Context, namespace, target string) ([]byte, error) {
+				return tt.served, tt.probeErr
+			})))
+
+			report, err := svc.ProbeTLSSecret(context.Background(), "default", tt.secretName, "example.com:443")
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("expected error: %v, got %v", tt.expectedErr, err)
+			}
+			if tt.expectedErr {
+				if !errors.Is(err, errRepo) {
+					t.Errorf("expected error %v, got %v", errRepo, err)
+				}
+				return
+			}
+
+			if report.Status != tt.expectedStatus {
+				t.Errorf("expected status %q, got %q", tt.expectedStatus, report.Status)
+			}
+			if len(report.Fingerprints) != tt.expectedDiffs {
+				t.Errorf("expected %d fingerprint diffs, got %d", tt.expectedDiffs, len(report.Fingerprints))
+			}
+			if report.Certificates[0].SubjectCommonName != "example.com" {
+				t.Errorf("expected the served leaf, got %s", report.Certificates[0].SubjectCommonName)
+			}
+		})
+	}
+
+	t.Run("Should return error if probing is not configured", func(t *testing.T) {
+		if _, err := service.NewSecretsService(secretRepo).ProbeTLSSecret(context.Background(), "default", "web-tls", "example.com:443"); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/repository"
+)
+
+// WithReferences looks up the Ingresses, Gateways and Routes serving each TLS secret.
+func WithReferences(repo repository.ReferencesRepository) Option {
+	return func(s *secretsService) {
+		s.references = repo
+	}
+}
+
+type referenceIndex map[domains.K8SResourceID][]domains.SecretReference
+
+// referenceIndex returns the references to the secrets of namespace by secret, it is reused for
+// lookupTTL.
+func (s secretsService) referenceIndex(ctx context.Context, namespace string) (referenceIndex, error) {
+	return s.referencesCache.get(namespace, func() (referenceIndex, error) {
+		return s.loadReferenceIndex(ctx, namespace)
+	})
+}
+
+func (s secretsService) loadReferenceIndex(ctx context.Context, namespace string) (referenceIndex, error) {
+	references, err := s.references.GetSecretReferences(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("can not look up secret references: %w", err)
+	}
+
+	index := referenceIndex{}
+	for _, reference := range references {
+		index[reference.Secret] = append(index[reference.Secret], reference)
+	}
+	return index, nil
+}
+
+// linkReferences sets UsedBy and UncoveredHosts of every inspection, or UsedByErr if the
+// references can not be listed.
+func (s secretsService) linkReferences(ctx context.Context, namespace string, inspections []TLSSecretInspection) {
+	if s.references == nil {
+		return
+	}
+
+	index, err := s.referenceIndex(ctx, namespace)
+	for i, inspection := range inspections {
+		if inspection.Kind != "" {
+			continue // Ingresses, Gateways and Routes only reference TLS secrets
+		}
+		if err != nil {
+			inspections[i].UsedByErr = err
+			continue
+		}
+		references := index[inspection.K8SResourceID]
+		inspections[i].UsedBy = []string{}
+		for _, reference := range references {
+			inspections[i].UsedBy = append(inspections[i].UsedBy, formatReference(reference))
+		}
+		if len(inspection.Certificates) > 0 {
+			inspections[i].UncoveredHosts = uncoveredHosts(inspection.Certificates[0].DNSNames, references)
+		}
+	}
+}
+
+// markUnused flags the summaries of secrets that no resource references. Summaries are left
+// untouched if the references can not be listed, the flag is only a hint.
+func (s secretsService) markUnused(ctx context.Context, namespace string, summaries []TLSSecretSummary) {
+	flagUnused(s.usageIndex(ctx, namespace), summaries)
+}
+
+// usageIndex returns the reference index markUnused flags with, nil if references are not
+// looked up or can not be listed.
+func (s secretsService) usageIndex(ctx context.Context, namespace string) referenceIndex {
+	if s.references == nil {
+		return nil
+	}
+
+	index, err := s.referenceIndex(ctx, namespace)
+	if err != nil {
+		return nil
+	}
+	return index
+}
+
+func flagUnused(index referenceIndex, summaries []TLSSecretSummary) {
+	if index == nil {
+		return
+	}
+	for i, summary := range summaries {
+		summaries[i].Unused = summary.Kind == "" && len(index[summary.K8SResourceID]) == 0
+	}
+}
+
+func referenceName(reference domains.SecretReference) string {
+	return fmt.Sprintf("%s %s/%s", reference.Kind, reference.Namespace, reference.Name)
+}
+
+func formatReference(reference domains.SecretReference) string {
+	text := referenceName(reference)
+	if len(reference.Hosts) > 0 {
+		text += " (" + strings.Join(reference.Hosts, ", ") + ")"
+	}
+	return text
+}
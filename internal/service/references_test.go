@@ -0,0 +1,88 @@
+package service_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/repository"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+func TestSecretReferences(t *testing.T) {
+	now := time.Now()
+	leaf := issueTestCertificate(t, "example.com", nil, false, now.Add(-time.Hour), now.Add(24*time.Hour))
+
+	secrets := []domains.SecretInfo{
+		{Name: "web-tls", Namespace: "default", TLSCert: pemBundle(leaf)},
+		{Name: "stale-tls", Namespace: "default", TLSCert: pemBundle(leaf)},
+	}
+	secretRepo := repository.NewMockRepository(func(ctx context.Context, namespace string) ([]domains.SecretInfo, error) {
+		return secrets, nil
+	}, nil, nil)
+
+	references := []domains.SecretReference{
+		{K8SResourceID: domains.K8SResourceID{Name: "web", Namespace: "default"}, Kind: "Ingress", Secret: domains.K8SResourceID{Name: "web-tls", Namespace: "default"}, Hosts: []string{"example.com"}},
+		{K8SResourceID: domains.K8SResourceID{Name: "gateway", Namespace: "default"}, Kind: "Gateway", Secret: domains.K8SResourceID{Name: "web-tls", Namespace: "default"}},
+		{K8SResourceID: domains.K8SResourceID{Name: "web", Namespace: "other"}, Kind: "Ingress", Secret: domains.K8SResourceID{Name: "stale-tls", Namespace: "other"}},
+	}
+
+	t.Run("Should list the resources using each secret", func(t *testing.T) {
+		svc := service.NewSecretsService(secretRepo, service.WithReferences(repository.NewMockReferencesRepository(func(ctx context.Context, namespace string) ([]domains.SecretReference, error) {
+			return references, nil
+		})))
+
+		inspections, err := svc.InspectTLSSecrets(context.Background(), "default")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := map[string][]string{
+			"web-tls":   {"Ingress default/web (example.com)", "Gateway default/gateway"},
+			"stale-tls": {},
+		}
+		for _, inspection := range inspections {
+			if !reflect.DeepEqual(inspection.UsedBy, expected[inspection.Name]) {
+				t.Errorf("expected %s to be used by %v, got %v", inspection.Name, expected[inspection.Name], inspection.UsedBy)
+			}
+		}
+
+		summaries, err := svc.ListTLSSecrets(context.Background(), "default")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, summary := range summaries {
+			if summary.Unused != (summary.Name == "stale-tls") {
+				t.Errorf("expected only stale-tls to be unused, %s unused: %v", summary.Name, summary.Unused)
+			}
+		}
+	})
+
+	t.Run("Should not fail when the references can not be listed", func(t *testing.T) {
+		svc := service.NewSecretsService(secretRepo, service.WithReferences(repository.NewMockReferencesRepository(func(ctx context.Context, namespace string) ([]domains.SecretReference, error) {
+			return nil, errRepo
+		})))
+
+		inspections, err := svc.InspectTLSSecrets(context.Background(), "default")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, inspection := range inspections {
+			if inspection.UsedBy != nil || inspection.UsedByErr == nil {
+				t.Errorf("expected the lookup error for %s, got %v", inspection.Name, inspection.UsedBy)
+			}
+		}
+
+		summaries, err := svc.ListTLSSecrets(context.Background(), "default")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, summary := range summaries {
+			if summary.Unused {
+				t.Errorf("expected %s not to be flagged unused without references", summary.Name)
+			}
+		}
+	})
+}
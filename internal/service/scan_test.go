@@ -0,0 +1,65 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/repository"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+func TestScannedResources(t *testing.T) {
+	now := time.Now()
+	ca := issueTestCertificate(t, "root", nil, true, now.Add(-time.Hour), now.Add(24*time.Hour))
+
+	scanned := domains.SecretInfo{Name: "kube-root-ca.crt", Namespace: "default", Kind: domains.KindConfigMap, CertKey: "ca.crt", TLSCert: pemBundle(ca)}
+	keystore := domains.SecretInfo{Name: "keystore", Namespace: "default", Kind: domains.KindSecret, Unsupported: map[string]string{"keystore.p12": "PKCS#12 keystores are password protected"}}
+	secretRepo := repository.NewMockRepository(func(ctx context.Context, namespace string) ([]domains.SecretInfo, error) {
+		return []domains.SecretInfo{scanned, keystore}, nil
+	}, nil, nil)
+	svc := service.NewSecretsService(secretRepo, service.WithReferences(repository.NewMockReferencesRepository(func(ctx context.Context, namespace string) ([]domains.SecretReference, error) {
+		return nil, nil
+	})))
+
+	t.Run("Should not report missing keys or unused resources for scanned resources", func(t *testing.T) {
+		summaries, err := svc.ListTLSSecrets(context.Background(), "default")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		summary := summaries[0]
+		if summary.KeyPair != service.KeyPairNotApplicable || summary.Unused {
+			t.Errorf("expected key N/A and not unused, got %v and unused %v", summary.KeyPair, summary.Unused)
+		}
+		if summary.Ref() != "ConfigMap/kube-root-ca.crt" {
+			t.Errorf("expected ref ConfigMap/kube-root-ca.crt, got %s", summary.Ref())
+		}
+	})
+
+	t.Run("Should record the data key the certificates were read from", func(t *testing.T) {
+		inspections, err := svc.InspectTLSSecrets(context.Background(), "default")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		inspection := inspections[0]
+		if inspection.CertKey != "ca.crt" || inspection.Kind != domains.KindConfigMap {
+			t.Errorf("expected ConfigMap data key ca.crt, got %s %s", inspection.Kind, inspection.CertKey)
+		}
+		if inspection.UsedBy != nil {
+			t.Errorf("expected no Used By for scanned resources, got %v", inspection.UsedBy)
+		}
+	})
+
+	t.Run("Should report the unsupported keys of resources without readable certificates", func(t *testing.T) {
+		inspections, err := svc.InspectTLSSecrets(context.Background(), "default")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(inspections) != 2 || !errors.Is(inspections[1].Err, service.ErrUnparsable) || !strings.Contains(inspections[1].Err.Error(), "keystore.p12: PKCS#12") {
+			t.Errorf("expected keystore.p12 to be reported as unsupported, got %+v", inspections)
+		}
+	})
+}
@@ -1,75 +1,333 @@
 package service
 
 import (
+	"context"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/codechamp1/certlens/internal/domains"
 	"github.com/codechamp1/certlens/internal/repository"
 )
 
+// ErrUnparsable is wrapped by the errors of secrets whose certificates can not be parsed.
+var ErrUnparsable = errors.New("can not parse TLS secret")
+
 type SecretsService interface {
-	InspectTLSSecret(namespace, name string) ([]CertificateInfo, error)
-	ListTLSSecrets(namespace string) ([]domains.K8SResourceID, error)
-	ListTLSSecret(namespace, name string) (domains.K8SResourceID, error)
-	RawInspectTLSSecret(namespace, name string) (string, string, error)
+	InspectTLSSecret(ctx context.Context, namespace, name string) (TLSSecretInspection, error)
+	InspectTLSSecrets(ctx context.Context, namespace string) ([]TLSSecretInspection, error)
+	ListTLSSecrets(ctx context.Context, namespace string) ([]TLSSecretSummary, error)
+	ListTLSSecretsPages(ctx context.Context, namespace string, page func([]TLSSecretSummary) error) error
+	ListTLSSecret(ctx context.Context, namespace, name string) (TLSSecretSummary, error)
+	RawInspectTLSSecret(ctx context.Context, namespace, name string) (string, string, error)
+	WatchTLSSecrets(ctx context.Context, namespace string) (<-chan TLSSecretEvent, error)
+	ProbeTLSSecret(ctx context.Context, namespace, name, target string) (ProbeReport, error)
+}
+
+// TLSSecretSummary is the per-secret data shown in the secrets list.
+type TLSSecretSummary struct {
+	domains.K8SResourceID
+	KeyPair KeyPairStatus
+
+	// Expiry of the leaf certificate
+	Expiry          Status
+	TimeUntilExpiry time.Duration
+
+	// Issuer of the leaf certificate, its common name if set
+	Issuer     string
+	SelfSigned bool
+
+	// Unused is set if no Ingress, Gateway or Route references the secret
+	Unused bool
+}
+
+// TLSSecretEvent is a live change of a TLS secret, carrying the updated list summary.
+type TLSSecretEvent struct {
+	Type    domains.SecretEventType
+	Summary TLSSecretSummary
+}
+
+// TLSSecretInspection holds everything certlens derives from a single TLS secret.
+type TLSSecretInspection struct {
+	domains.K8SResourceID
+	// CertKey is the data key the certificates were read from, tls.crt for TLS secrets.
+	CertKey      string
+	Certificates []CertificateInfo
+	Chain        ChainReport
+
+	// Findings are the lint rules violated by the certificates.
+	Findings []Finding
+
+	// PrivateKey describes tls.key, it is nil if the secret holds no key.
+	PrivateKey *PrivateKeyInfo
+
+	// Bundles are the certificates of ca.crt and other PEM keys of the secret.
+	Bundles []PEMBundle
+
+	// CertManager is set when the secret is issued by a cert-manager Certificate, CertManagerErr
+	// when the cert-manager resources can not be listed.
+	CertManager    *CertManagerReport
+	CertManagerErr error
+
+	// UsedBy lists the resources serving the secret, it is nil if references are not looked up.
+	UsedBy    []string
+	UsedByErr error
+
+	// UncoveredHosts are served with the secret by a referencing resource, but not covered by the leaf SANs.
+	UncoveredHosts []string
+
+	// Err is set when a listed secret could not be parsed, see InspectTLSSecrets.
+	Err error
 }
 
 type secretsService struct {
 	repository.SecretsRepository
+	trustBundle []*x509.Certificate
+	certManager repository.CertManagerRepository
+	references  repository.ReferencesRepository
+	prober      repository.ProbeRepository
+	lintRules   []LintRule
+	expiry      ExpiryPolicy
+
+	certManagerCache *lookupCache[certManagerResources]
+	referencesCache  *lookupCache[referenceIndex]
+}
+
+type Option func(*secretsService)
+
+// WithTrustBundle sets the root certificates used for chain validation instead of the system roots.
+func WithTrustBundle(roots []*x509.Certificate) Option {
+	return func(s *secretsService) {
+		s.trustBundle = roots
+	}
 }
 
-func NewSecretsService(repo repository.SecretsRepository) SecretsService {
-	return secretsService{
+func NewSecretsService(repo repository.SecretsRepository, opts ...Option) SecretsService {
+	svc := secretsService{
 		SecretsRepository: repo,
+		lintRules:         DefaultLintRules(),
+		expiry:            ExpiryPolicy{ExpiryThresholds: DefaultExpiryThresholds},
+		certManagerCache:  newLookupCache[certManagerResources](),
+		referencesCache:   newLookupCache[referenceIndex](),
 	}
+	for _, opt := range opts {
+		opt(&svc)
+	}
+	return svc
 }
 
-func (s secretsService) InspectTLSSecret(namespace, name string) ([]CertificateInfo, error) {
-	secret, err := s.GetTLSSecret(namespace, name)
+func (s secretsService) InspectTLSSecret(ctx context.Context, namespace, name string) (TLSSecretInspection, error) {
+	secret, err := s.GetTLSSecret(ctx, namespace, name)
+	if err != nil {
+		return TLSSecretInspection{}, fmt.Errorf("can not inspect TLS secret: %w", err)
+	}
+
+	inspection, err := s.inspectSecret(secret)
 	if err != nil {
-		return nil, fmt.Errorf("can not inspect TLS secret: %w", err)
+		return TLSSecretInspection{}, err
+	}
+
+	inspections := []TLSSecretInspection{inspection}
+	s.linkCertManager(ctx, secret.Namespace, inspections)
+	s.linkReferences(ctx, secret.Namespace, inspections)
+
+	return inspections[0], nil
+}
+
+// InspectTLSSecrets inspects every TLS secret in the namespace. Secrets that can not be parsed
+// are still returned, with Err describing why. When some sources of the repository fail, the
+// inspections of the others are returned with a domains.PartialError.
+func (s secretsService) InspectTLSSecrets(ctx context.Context, namespace string) ([]TLSSecretInspection, error) {
+	secrets, err := s.GetTLSSecrets(ctx, namespace)
+	var partial *domains.PartialError
+	if err != nil && !errors.As(err, &partial) {
+		return nil, fmt.Errorf("can not list TLS secrets: %w", err)
+	}
+
+	inspections := make([]TLSSecretInspection, 0, len(secrets))
+	for _, secret := range secrets {
+		inspection, err := s.inspectSecret(secret)
+		if err != nil {
+			inspection = TLSSecretInspection{
+				K8SResourceID: secret.ID(),
+				Err:           err,
+			}
+		}
+		inspections = append(inspections, inspection)
+	}
+
+	s.linkCertManager(ctx, namespace, inspections)
+	s.linkReferences(ctx, namespace, inspections)
+
+	if partial != nil {
+		return inspections, partial
+	}
+	return inspections, nil
+}
+
+func (s secretsService) inspectSecret(secret domains.SecretInfo) (TLSSecretInspection, error) {
+	if len(secret.TLSCert) == 0 && len(secret.Unsupported) > 0 {
+		return TLSSecretInspection{}, fmt.Errorf("%w: %s", ErrUnparsable, unsupportedReasons(secret))
 	}
 
 	certData, err := parseCertsFromString(string(secret.TLSCert))
 
 	if err != nil {
-		return nil, fmt.Errorf("can not parse TLS secret: %w", err)
+		return TLSSecretInspection{}, fmt.Errorf("%w: %w", ErrUnparsable, err)
 	}
 
-	parsedCert := parseCertificates(certData)
+	// ca.crt is optional, an unparsable bundle only means there are no extra trust anchors
+	caCerts, _ := parseCertsFromString(string(secret.CACert))
+
+	thresholds := s.expiry.For(secret.Namespace)
+	parsedCert := parseCertificates(certData, thresholds)
+	parsedCert[0].KeyMatches = keyPairStatus(secret, certData[0]).String()
+
+	now := time.Now()
+	chain := verifyChain(certData, caCerts, s.trustBundle, now)
+	public := publiclyTrusted(certData, caCerts, chain, s.trustBundle, now)
 
-	return parsedCert, nil
+	return TLSSecretInspection{
+		K8SResourceID: secret.ID(),
+		CertKey:       secret.CertKey,
+		Certificates:  parsedCert,
+		Chain:         chain,
+		Findings:      lint(s.lintRules, secret, certData, parsedCert, public),
+		PrivateKey:    inspectPrivateKey(secret.TLSKey),
+		Bundles:       parseBundles(secret, thresholds),
+	}, nil
 }
 
-func (s secretsService) ListTLSSecrets(namespace string) ([]domains.K8SResourceID, error) {
-	secrets, err := s.GetTLSSecrets(namespace)
+func (s secretsService) ListTLSSecrets(ctx context.Context, namespace string) ([]TLSSecretSummary, error) {
+	var summaries []TLSSecretSummary
+	err := s.ListTLSSecretsPages(ctx, namespace, func(page []TLSSecretSummary) error {
+		summaries = append(summaries, page...)
+		return nil
+	})
 
+	var partial *domains.PartialError
+	if errors.As(err, &partial) {
+		return summaries, partial
+	}
 	if err != nil {
-		return nil, fmt.Errorf("can not list TLS secrets: %w", err)
+		return nil, err
 	}
 
-	var tlsSecretsNames []domains.K8SResourceID
-	for _, secret := range secrets {
-		tlsSecretsNames = append(tlsSecretsNames, domains.K8SResourceID{Name: secret.Name, Namespace: secret.Namespace})
+	return summaries, nil
+}
+
+// ListTLSSecretsPages summarizes the secrets page by page as the repository streams them, so
+// large clusters can be shown before they are listed completely. A domains.PartialError is
+// returned as is, after the pages of the sources that could be read.
+func (s secretsService) ListTLSSecretsPages(ctx context.Context, namespace string, page func([]TLSSecretSummary) error) error {
+	index := s.usageIndex(ctx, namespace)
+	err := s.GetTLSSecretsPages(ctx, namespace, func(secrets []domains.SecretInfo) error {
+		summaries := make([]TLSSecretSummary, 0, len(secrets))
+		for _, secret := range secrets {
+			summaries = append(summaries, s.summarizeSecret(secret))
+		}
+		flagUnused(index, summaries)
+		return page(summaries)
+	})
+
+	var partial *domains.PartialError
+	if errors.As(err, &partial) {
+		return partial
+	}
+	if err != nil {
+		return fmt.Errorf("can not list TLS secrets: %w", err)
 	}
 
-	return tlsSecretsNames, nil
+	return nil
 }
 
-func (s secretsService) ListTLSSecret(namespace, name string) (domains.K8SResourceID, error) {
-	secret, err := s.GetTLSSecret(namespace, name)
+func (s secretsService) ListTLSSecret(ctx context.Context, namespace, name string) (TLSSecretSummary, error) {
+	secret, err := s.GetTLSSecret(ctx, namespace, name)
 	if err != nil {
-		return domains.K8SResourceID{}, fmt.Errorf("failed to get TLS secret %s in namespace %s: %w", name, namespace, err)
+		return TLSSecretSummary{}, fmt.Errorf("failed to get TLS secret %s in namespace %s: %w", name, namespace, err)
 	}
 
-	return domains.K8SResourceID{Name: secret.Name, Namespace: secret.Namespace}, nil
+	summaries := []TLSSecretSummary{s.summarizeSecret(secret)}
+	s.markUnused(ctx, secret.Namespace, summaries)
+	return summaries[0], nil
 }
 
-func (s secretsService) RawInspectTLSSecret(namespace, name string) (cert string, key string, err error) {
-	secret, err := s.GetTLSSecret(namespace, name)
+func (s secretsService) RawInspectTLSSecret(ctx context.Context, namespace, name string) (cert string, key string, err error) {
+	secret, err := s.GetTLSSecret(ctx, namespace, name)
 	if err != nil {
 		return "", "", fmt.Errorf("can not inspect TLS secret: %w", err)
 	}
 
 	return string(secret.TLSCert), string(secret.TLSKey), nil
 }
+
+func (s secretsService) WatchTLSSecrets(ctx context.Context, namespace string) (<-chan TLSSecretEvent, error) {
+	secretEvents, err := s.SecretsRepository.WatchTLSSecrets(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("can not watch TLS secrets: %w", err)
+	}
+
+	events := make(chan TLSSecretEvent)
+	go func() {
+		defer close(events)
+
+		// the references are refreshed on a timer rather than listed for every event
+		index := s.usageIndex(ctx, namespace)
+		refresh := time.NewTicker(lookupTTL)
+		defer refresh.Stop()
+
+		for {
+			var event domains.SecretEvent
+			var ok bool
+			select {
+			case event, ok = <-secretEvents:
+			case <-refresh.C:
+				if s.references != nil {
+					if refreshed, err := s.loadReferenceIndex(ctx, namespace); err == nil {
+						index = refreshed
+					}
+				}
+				continue
+			}
+			if !ok {
+				return
+			}
+
+			summaries := []TLSSecretSummary{s.summarizeSecret(event.Secret)}
+			if event.Type != domains.SecretDeleted {
+				flagUnused(index, summaries)
+			}
+			select {
+			case events <- TLSSecretEvent{Type: event.Type, Summary: summaries[0]}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+func (s secretsService) summarizeSecret(secret domains.SecretInfo) TLSSecretSummary {
+	summary := TLSSecretSummary{
+		K8SResourceID: secret.ID(),
+		KeyPair:       KeyPairUnknown,
+		Expiry:        StatusUnknown,
+	}
+
+	certs, err := parseCertsFromString(string(secret.TLSCert))
+	if err != nil {
+		return summary
+	}
+
+	leaf := certs[0]
+	_, summary.Expiry = expiryStatus(*leaf, s.expiry.For(secret.Namespace))
+	summary.TimeUntilExpiry = time.Until(leaf.NotAfter)
+	summary.KeyPair = keyPairStatus(secret, leaf)
+	summary.Issuer = leaf.Issuer.CommonName
+	if summary.Issuer == "" {
+		summary.Issuer = leaf.Issuer.String()
+	}
+	summary.SelfSigned = leaf.CheckSignatureFrom(leaf) == nil
+	return summary
+}
@@ -1,9 +1,21 @@
 package service_test
 
 import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
+	"math/big"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/codechamp1/certlens/internal/domains"
 	"github.com/codechamp1/certlens/internal/repository"
@@ -13,7 +25,7 @@ import (
 var errRepo = errors.New("simulated error")
 
 func TestNewSecretsService(t *testing.T) {
-	mockRepo := repository.NewMockRepository(nil, nil)
+	mockRepo := repository.NewMockRepository(nil, nil, nil)
 	svc := service.NewSecretsService(mockRepo)
 	if svc == nil {
 		t.Error("secrets service should not be nil")
@@ -29,18 +41,18 @@ func TestListTLSSecrets(t *testing.T) {
 		name              string
 		namespace         string
 		secrets           []domains.SecretInfo
-		expectedSecretIDs []domains.K8SResourceID
+		expectedSecretIDs []service.TLSSecretSummary
 		expectedRepoErr   error
 	}{
 		{
 			name:              "Should return error if can not fetch secrets",
 			namespace:         "",
 			secrets:           []domains.SecretInfo{},
-			expectedSecretIDs: []domains.K8SResourceID{},
+			expectedSecretIDs: []service.TLSSecretSummary{},
 			expectedRepoErr:   errRepo,
 		},
 		{
-			name:      "Should transform all TLS secrets to K8SResourceID",
+			name:      "Should summarize all TLS secrets",
 			namespace: "default",
 			secrets: []domains.SecretInfo{
 				{
@@ -58,28 +70,39 @@ func TestListTLSSecrets(t *testing.T) {
 					TLSKey:    []byte("key-data"),
 				},
 			},
-			expectedSecretIDs: []domains.K8SResourceID{
+			expectedSecretIDs: []service.TLSSecretSummary{
+				{K8SResourceID: domains.K8SResourceID{Name: "tls-secret-1", Namespace: "default"}, KeyPair: service.KeyPairUnknown},
+				{K8SResourceID: domains.K8SResourceID{Name: "tls-secret-2", Namespace: "default"}, KeyPair: service.KeyPairUnknown},
+			},
+		},
+		{
+			name:      "Should keep the secrets if some sources fail",
+			namespace: "",
+			secrets: []domains.SecretInfo{
 				{Name: "tls-secret-1", Namespace: "default"},
-				{Name: "tls-secret-2", Namespace: "default"},
 			},
+			expectedSecretIDs: []service.TLSSecretSummary{
+				{K8SResourceID: domains.K8SResourceID{Name: "tls-secret-1", Namespace: "default"}, KeyPair: service.KeyPairUnknown},
+			},
+			expectedRepoErr: &domains.PartialError{Errs: []error{errRepo}},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockRepo := repository.NewMockRepository(func(namespace string) ([]domains.SecretInfo, error) {
+			mockRepo := repository.NewMockRepository(func(ctx context.Context, namespace string) ([]domains.SecretInfo, error) {
 				return tt.secrets, tt.expectedRepoErr
-			}, nil)
+			}, nil, nil)
 
 			svc := service.NewSecretsService(mockRepo)
-			secrets, err := svc.ListTLSSecrets(tt.namespace)
+			secrets, err := svc.ListTLSSecrets(context.Background(), tt.namespace)
 
 			if !errors.Is(err, tt.expectedRepoErr) {
 				t.Errorf("expected error %v, got %v", tt.expectedRepoErr, err)
 			}
 
 			if secrets == nil {
-				secrets = []domains.K8SResourceID{}
+				secrets = []service.TLSSecretSummary{}
 			}
 
 			if !reflect.DeepEqual(secrets, tt.expectedSecretIDs) {
@@ -94,18 +117,18 @@ func TestListTLSSecret(t *testing.T) {
 		name             string
 		namespace        string
 		secret           domains.SecretInfo
-		expectedSecretID domains.K8SResourceID
+		expectedSecretID service.TLSSecretSummary
 		expectedRepoErr  error
 	}{
 		{
 			name:             "Should return error if can not fetch secret",
 			namespace:        "default",
 			secret:           domains.SecretInfo{},
-			expectedSecretID: domains.K8SResourceID{},
+			expectedSecretID: service.TLSSecretSummary{},
 			expectedRepoErr:  errRepo,
 		},
 		{
-			name:      "Should return K8SResourceID for a single TLS secret",
+			name:      "Should return the summary of a single TLS secret",
 			namespace: "default",
 			secret: domains.SecretInfo{
 				Name:      "tls-secret-1",
@@ -114,19 +137,22 @@ func TestListTLSSecret(t *testing.T) {
 				TLSCert:   []byte("cert-data"),
 				TLSKey:    []byte("key-data"),
 			},
-			expectedSecretID: domains.K8SResourceID{Name: "tls-secret-1", Namespace: "default"},
-			expectedRepoErr:  nil,
+			expectedSecretID: service.TLSSecretSummary{
+				K8SResourceID: domains.K8SResourceID{Name: "tls-secret-1", Namespace: "default"},
+				KeyPair:       service.KeyPairUnknown,
+			},
+			expectedRepoErr: nil,
 		},
 	}
 
 	for _, tt := range test {
 		t.Run(tt.name, func(t *testing.T) {
-			mockRepo := repository.NewMockRepository(nil, func(namespace, name string) (domains.SecretInfo, error) {
+			mockRepo := repository.NewMockRepository(nil, func(ctx context.Context, namespace, name string) (domains.SecretInfo, error) {
 				return tt.secret, tt.expectedRepoErr
-			})
+			}, nil)
 
 			svc := service.NewSecretsService(mockRepo)
-			secretID, err := svc.ListTLSSecret(tt.namespace, tt.secret.Name)
+			secretID, err := svc.ListTLSSecret(context.Background(), tt.namespace, tt.secret.Name)
 
 			if !errors.Is(err, tt.expectedRepoErr) {
 				t.Errorf("expected error %v, got %v", tt.expectedRepoErr, err)
@@ -176,12 +202,12 @@ func TestRawInspectTLSSecret(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockRepo := repository.NewMockRepository(nil, func(namespace, name string) (domains.SecretInfo, error) {
+			mockRepo := repository.NewMockRepository(nil, func(ctx context.Context, namespace, name string) (domains.SecretInfo, error) {
 				return tt.secret, tt.expectedRepoErr
-			})
+			}, nil)
 
 			svc := service.NewSecretsService(mockRepo)
-			cert, key, err := svc.RawInspectTLSSecret(tt.namespace, tt.secretName)
+			cert, key, err := svc.RawInspectTLSSecret(context.Background(), tt.namespace, tt.secretName)
 
 			if !errors.Is(err, tt.expectedRepoErr) {
 				t.Errorf("expected error %v, got %v", tt.expectedRepoErr, err)
@@ -197,3 +223,309 @@ func TestRawInspectTLSSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestKeyPairStatus(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	pkcs8 := func(key any) []byte {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			t.Fatalf("failed to marshal key: %v", err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	}
+	sec1, _ := x509.MarshalECPrivateKey(ecKey)
+
+	tests := []struct {
+		name     string
+		kind     string
+		fileType string
+		cert     []byte
+		key      []byte
+		expected service.KeyPairStatus
+	}{
+		{
+			name:     "Should match a PKCS#1 RSA key",
+			cert:     newTestCertificate(t, rsaKey),
+			key:      pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}),
+			expected: service.KeyPairMatch,
+		},
+		{
+			name:     "Should match a PKCS#8 RSA key",
+			cert:     newTestCertificate(t, rsaKey),
+			key:      pkcs8(rsaKey),
+			expected: service.KeyPairMatch,
+		},
+		{
+			name:     "Should match a SEC1 EC key preceded by EC parameters",
+			cert:     newTestCertificate(t, ecKey),
+			key:      append([]byte("-----BEGIN EC PARAMETERS-----\nBggqhkjOPQMBBw==\n-----END EC PARAMETERS-----\n"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1})...),
+			expected: service.KeyPairMatch,
+		},
+		{
+			name:     "Should match a PKCS#8 Ed25519 key",
+			cert:     newTestCertificate(t, edKey),
+			key:      pkcs8(edKey),
+			expected: service.KeyPairMatch,
+		},
+		{
+			name:     "Should report a mismatch when the key belongs to another certificate",
+			cert:     newTestCertificate(t, rsaKey),
+			key:      pkcs8(ecKey),
+			expected: service.KeyPairMismatch,
+		},
+		{
+			name:     "Should report a missing key",
+			cert:     newTestCertificate(t, rsaKey),
+			key:      nil,
+			expected: service.KeyPairMissing,
+		},
+		{
+			name:     "Should report an invalid key",
+			cert:     newTestCertificate(t, rsaKey),
+			key:      []byte("key-data"),
+			expected: service.KeyPairInvalid,
+		},
+		{
+			name:     "Should not apply to scanned resources without a key",
+			kind:     domains.KindConfigMap,
+			cert:     newTestCertificate(t, rsaKey),
+			expected: service.KeyPairNotApplicable,
+		},
+		{
+			name:     "Should not apply to local files without a key",
+			fileType: domains.FileSecretType,
+			cert:     newTestCertificate(t, rsaKey),
+			expected: service.KeyPairNotApplicable,
+		},
+		{
+			name:     "Should check the key of local files",
+			fileType: domains.FileSecretType,
+			cert:     newTestCertificate(t, rsaKey),
+			key:      pkcs8(ecKey),
+			expected: service.KeyPairMismatch,
+		},
+		{
+			name:     "Should not apply to the caBundles of webhooks, CRDs and APIServices",
+			kind:     domains.KindValidatingWebhookConfiguration,
+			cert:     newTestCertificate(t, rsaKey),
+			expected: service.KeyPairNotApplicable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockRepository(nil, func(ctx context.Context, namespace, name string) (domains.SecretInfo, error) {
+				return domains.SecretInfo{Name: name, Namespace: namespace, Kind: tt.kind, Type: tt.fileType, TLSCert: tt.cert, TLSKey: tt.key}, nil
+			}, nil)
+
+			svc := service.NewSecretsService(mockRepo)
+
+			summary, err := svc.ListTLSSecret(context.Background(), "default", "tls-secret")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if summary.KeyPair != tt.expected {
+				t.Errorf("expected key pair status %v, got %v", tt.expected, summary.KeyPair)
+			}
+			if summary.Expiry.String() != "OK" || summary.TimeUntilExpiry <= 0 {
+				t.Errorf("expected leaf expiry to be summarized, got %v in %v", summary.Expiry, summary.TimeUntilExpiry)
+			}
+
+			inspection, err := svc.InspectTLSSecret(context.Background(), "default", "tls-secret")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if certs := inspection.Certificates; certs[0].KeyMatches != tt.expected.String() {
+				t.Errorf("expected key matches %q, got %q", tt.expected.String(), certs[0].KeyMatches)
+			}
+		})
+	}
+}
+
+func TestInspectPrivateKey(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	weakKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	sec1, _ := x509.MarshalECPrivateKey(ecKey)
+	weakPKCS8, _ := x509.MarshalPKCS8PrivateKey(weakKey)
+	edPKCS8, _ := x509.MarshalPKCS8PrivateKey(edKey)
+
+	tests := []struct {
+		name     string
+		cert     []byte
+		key      []byte
+		expected *service.PrivateKeyInfo
+	}{
+		{
+			name:     "Should describe a PKCS#1 RSA key",
+			cert:     newTestCertificate(t, rsaKey),
+			key:      pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}),
+			expected: &service.PrivateKeyInfo{Type: "RSA", Bits: 2048, Encoding: "PKCS#1"},
+		},
+		{
+			name: "Should flag a small RSA key",
+			cert: newTestCertificate(t, weakKey),
+			key:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: weakPKCS8}),
+			expected: &service.PrivateKeyInfo{Type: "RSA", Bits: 1024, Encoding: "PKCS#8",
+				Weaknesses: []string{"RSA key of 1024 bits is smaller than 2048 bits"}},
+		},
+		{
+			name:     "Should describe a SEC1 EC key with its curve",
+			cert:     newTestCertificate(t, ecKey),
+			key:      pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1}),
+			expected: &service.PrivateKeyInfo{Type: "ECDSA", Bits: 384, Curve: "P-384", Encoding: "SEC1"},
+		},
+		{
+			name:     "Should describe a PKCS#8 Ed25519 key",
+			cert:     newTestCertificate(t, edKey),
+			key:      pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edPKCS8}),
+			expected: &service.PrivateKeyInfo{Type: "Ed25519", Bits: 256, Curve: "Ed25519", Encoding: "PKCS#8"},
+		},
+		{
+			name:     "Should report an encrypted PKCS#8 key",
+			cert:     newTestCertificate(t, rsaKey),
+			key:      pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: []byte("encrypted")}),
+			expected: &service.PrivateKeyInfo{Encoding: "PKCS#8", Encrypted: true},
+		},
+		{
+			name: "Should report a legacy encrypted PKCS#1 key",
+			cert: newTestCertificate(t, rsaKey),
+			key: pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: []byte("encrypted"),
+				Headers: map[string]string{"Proc-Type": "4,ENCRYPTED", "DEK-Info": "AES-256-CBC,00"}}),
+			expected: &service.PrivateKeyInfo{Type: "RSA", Encoding: "PKCS#1", Encrypted: true},
+		},
+		{
+			name:     "Should report data without a private key",
+			cert:     newTestCertificate(t, rsaKey),
+			key:      []byte("key-data"),
+			expected: &service.PrivateKeyInfo{Error: "no private key found in input"},
+		},
+		{
+			name:     "Should omit a missing key",
+			cert:     newTestCertificate(t, rsaKey),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockRepository(nil, func(ctx context.Context, namespace, name string) (domains.SecretInfo, error) {
+				return domains.SecretInfo{Name: name, Namespace: namespace, TLSCert: tt.cert, TLSKey: tt.key}, nil
+			}, nil)
+
+			inspection, err := service.NewSecretsService(mockRepo).InspectTLSSecret(context.Background(), "default", "tls-secret")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(inspection.PrivateKey, tt.expected) {
+				t.Errorf("expected private key info %+v, got %+v", tt.expected, inspection.PrivateKey)
+			}
+		})
+	}
+}
+
+func newTestCertificate(t *testing.T, key crypto.Signer) []byte {
+	t.Helper()
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestWatchTLSSecrets(t *testing.T) {
+	t.Run("Should return error if the repository can not watch secrets", func(t *testing.T) {
+		mockRepo := repository.NewMockRepository(nil, nil, func(ctx context.Context, namespace string) (<-chan domains.SecretEvent, error) {
+			return nil, errRepo
+		})
+
+		svc := service.NewSecretsService(mockRepo)
+		if _, err := svc.WatchTLSSecrets(context.Background(), "default"); !errors.Is(err, errRepo) {
+			t.Errorf("expected error %v, got %v", errRepo, err)
+		}
+	})
+
+	t.Run("Should summarize every secret event", func(t *testing.T) {
+		repoEvents := make(chan domains.SecretEvent, 1)
+		repoEvents <- domains.SecretEvent{
+			Type:   domains.SecretModified,
+			Secret: domains.SecretInfo{Name: "tls-secret-1", Namespace: "default", TLSCert: []byte("cert-data")},
+		}
+		close(repoEvents)
+
+		mockRepo := repository.NewMockRepository(nil, nil, func(ctx context.Context, namespace string) (<-chan domains.SecretEvent, error) {
+			return repoEvents, nil
+		})
+
+		svc := service.NewSecretsService(mockRepo)
+		events, err := svc.WatchTLSSecrets(context.Background(), "default")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var got []service.TLSSecretEvent
+		for event := range events {
+			got = append(got, event)
+		}
+
+		expected := []service.TLSSecretEvent{
+			{
+				Type: domains.SecretModified,
+				Summary: service.TLSSecretSummary{
+					K8SResourceID: domains.K8SResourceID{Name: "tls-secret-1", Namespace: "default"},
+					KeyPair:       service.KeyPairUnknown,
+				},
+			},
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected events %+v, got %+v", expected, got)
+		}
+	})
+
+	t.Run("Should flag unused secrets without listing the references for every event", func(t *testing.T) {
+		repoEvents := make(chan domains.SecretEvent, 2)
+		repoEvents <- domains.SecretEvent{Type: domains.SecretAdded, Secret: domains.SecretInfo{Name: "used-tls", Namespace: "default"}}
+		repoEvents <- domains.SecretEvent{Type: domains.SecretAdded, Secret: domains.SecretInfo{Name: "unused-tls", Namespace: "default"}}
+		close(repoEvents)
+
+		mockRepo := repository.NewMockRepository(nil, nil, func(ctx context.Context, namespace string) (<-chan domains.SecretEvent, error) {
+			return repoEvents, nil
+		})
+		var lists int
+		references := repository.NewMockReferencesRepository(func(ctx context.Context, namespace string) ([]domains.SecretReference, error) {
+			lists++
+			return []domains.SecretReference{{Kind: "Ingress", Secret: domains.K8SResourceID{Name: "used-tls", Namespace: "default"}}}, nil
+		})
+
+		svc := service.NewSecretsService(mockRepo, service.WithReferences(references))
+		events, err := svc.WatchTLSSecrets(context.Background(), "default")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		unused := map[string]bool{}
+		for event := range events {
+			unused[event.Summary.Name] = event.Summary.Unused
+		}
+		if unused["used-tls"] || !unused["unused-tls"] {
+			t.Errorf("expected only unused-tls to be flagged, got %v", unused)
+		}
+		if lists != 1 {
+			t.Errorf("expected the references to be listed once, got %d", lists)
+		}
+	})
+}
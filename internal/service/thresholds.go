@@ -0,0 +1,135 @@
+package service
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"time"
+)
+
+// Threshold is the remaining validity at which a certificate changes its expiry status, either
+// an absolute duration or a percentage of its total validity. The zero value is never reached.
+type Threshold struct {
+	Duration time.Duration
+	Percent  float64
+}
+
+func (t Threshold) String() string {
+	if t.Percent > 0 {
+		return strconv.FormatFloat(t.Percent, 'f', -1, 64) + "%"
+	}
+	return t.Duration.String()
+}
+
+func (t Threshold) IsZero() bool {
+	return t.Percent == 0 && t.Duration == 0
+}
+
+func (t Threshold) reached(remaining time.Duration, percentRemaining float64) bool {
+	switch {
+	case t.Percent > 0:
+		return percentRemaining <= t.Percent
+	case t.Duration > 0:
+		return remaining <= t.Duration
+	default:
+		return false
+	}
+}
+
+// ExpiryThresholds decide when a certificate is reported as warning or critical.
+type ExpiryThresholds struct {
+	Warning  Threshold
+	Critical Threshold
+}
+
+// Validate rejects a critical threshold that is not below the warning threshold. A zero
+// critical threshold is never reached and always below, a zero warning threshold is only
+// above a zero critical threshold. Thresholds of different kinds (a duration and a
+// percentage) can not be compared and are accepted.
+func (t ExpiryThresholds) Validate() error {
+	var ordered bool
+	switch {
+	case t.Critical.IsZero():
+		return nil
+	case t.Warning.IsZero():
+		ordered = false
+	case t.Warning.Percent > 0 && t.Critical.Percent > 0:
+		ordered = t.Critical.Percent < t.Warning.Percent
+	case t.Warning.Duration > 0 && t.Critical.Duration > 0:
+		ordered = t.Critical.Duration < t.Warning.Duration
+	default:
+		return nil
+	}
+	if !ordered {
+		return fmt.Errorf("critical threshold %s must be below the warning threshold %s", t.Critical, t.Warning)
+	}
+	return nil
+}
+
+// DefaultExpiryThresholds warn at 25% and turn critical at 10% of the validity remaining.
+var DefaultExpiryThresholds = ExpiryThresholds{
+	Warning:  Threshold{Percent: 25},
+	Critical: Threshold{Percent: 10},
+}
+
+// NamespaceThresholds override the thresholds in the namespaces matching the Match glob,
+// omitted (nil) thresholds are inherited from the defaults.
+type NamespaceThresholds struct {
+	Match    string
+	Warning  *Threshold
+	Critical *Threshold
+}
+
+// ExpiryPolicy holds the default thresholds and the overrides of single namespaces, the
+// first matching override applies.
+type ExpiryPolicy struct {
+	ExpiryThresholds
+	Namespaces []NamespaceThresholds
+}
+
+// WithExpiryPolicy replaces the DefaultExpiryThresholds used for the expiry status of every certificate.
+func WithExpiryPolicy(policy ExpiryPolicy) Option {
+	return func(s *secretsService) {
+		s.expiry = policy
+	}
+}
+
+// Validate checks the default thresholds and the thresholds of every namespace, which must
+// have a valid Match glob.
+func (p ExpiryPolicy) Validate() error {
+	if err := p.ExpiryThresholds.Validate(); err != nil {
+		return err
+	}
+	for _, namespace := range p.Namespaces {
+		if _, err := path.Match(namespace.Match, ""); err != nil || namespace.Match == "" {
+			return fmt.Errorf("invalid namespace pattern %q", namespace.Match)
+		}
+		if err := namespace.apply(p.ExpiryThresholds).Validate(); err != nil {
+			return fmt.Errorf("namespaces %s: %w", namespace.Match, err)
+		}
+	}
+	return nil
+}
+
+// For returns the thresholds of the namespace.
+func (p ExpiryPolicy) For(namespace string) ExpiryThresholds {
+	thresholds := p.ExpiryThresholds
+	for _, override := range p.Namespaces {
+		if ok, _ := path.Match(override.Match, namespace); ok {
+			return override.apply(thresholds)
+		}
+	}
+	return thresholds
+}
+
+// apply overrides the defaults with the thresholds set in the namespace.
+func (n NamespaceThresholds) apply(defaults ExpiryThresholds) ExpiryThresholds {
+	thresholds := defaults
+	if n.Warning != nil {
+		thresholds.Warning = *n.Warning
+	}
+	if n.Critical != nil {
+		thresholds.Critical = *n.Critical
+	}
+	return thresholds
+}
@@ -0,0 +1,125 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/repository"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+func TestExpiryThresholds(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+
+	// a 10 year CA with 2 years left and a 24h certificate with 3 hours left
+	ca := issueTestCertificate(t, "ca", nil, true, now.Add(-8*365*day), now.Add(2*365*day))
+	daily := issueTestCertificate(t, "daily", nil, false, now.Add(-21*time.Hour), now.Add(3*time.Hour))
+
+	absolute := service.ExpiryThresholds{
+		Warning:  service.Threshold{Duration: 30 * day},
+		Critical: service.Threshold{Duration: 7 * day},
+	}
+
+	tests := []struct {
+		name      string
+		cert      *testCA
+		namespace string
+		opts      []service.Option
+		expected  string
+	}{
+		{
+			name:     "Should warn years ahead for long-lived CAs by default",
+			cert:     ca,
+			expected: "Warning",
+		},
+		{
+			name:     "Should not warn a long-lived CA with absolute thresholds",
+			cert:     ca,
+			opts:     []service.Option{service.WithExpiryPolicy(service.ExpiryPolicy{ExpiryThresholds: absolute})},
+			expected: "OK",
+		},
+		{
+			name:     "Should report a short-lived certificate as critical with absolute thresholds",
+			cert:     daily,
+			opts:     []service.Option{service.WithExpiryPolicy(service.ExpiryPolicy{ExpiryThresholds: absolute})},
+			expected: "Critical",
+		},
+		{
+			name:      "Should apply the thresholds of a matching namespace",
+			cert:      daily,
+			namespace: "batch-jobs",
+			opts: []service.Option{service.WithExpiryPolicy(service.ExpiryPolicy{
+				ExpiryThresholds: absolute,
+				Namespaces: []service.NamespaceThresholds{
+					{Match: "batch-*", Critical: &service.Threshold{Percent: 5}},
+				},
+			})},
+			expected: "Warning",
+		},
+		{
+			name:      "Should never turn critical in a namespace overriding the critical threshold with 0",
+			cert:      daily,
+			namespace: "batch-jobs",
+			opts: []service.Option{service.WithExpiryPolicy(service.ExpiryPolicy{
+				ExpiryThresholds: absolute,
+				Namespaces: []service.NamespaceThresholds{
+					{Match: "batch-*", Critical: &service.Threshold{}},
+				},
+			})},
+			expected: "Warning",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockRepository(nil, func(ctx context.Context, namespace, name string) (domains.SecretInfo, error) {
+				return domains.SecretInfo{Name: name, Namespace: namespace, TLSCert: pemBundle(tt.cert)}, nil
+			}, nil)
+			svc := service.NewSecretsService(mockRepo, tt.opts...)
+
+			inspection, err := svc.InspectTLSSecret(context.Background(), tt.namespace, "tls-secret")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if status := inspection.Certificates[0].ExpiryStatus; status != tt.expected {
+				t.Errorf("expected expiry status %s, got %s", tt.expected, status)
+			}
+
+			summary, err := svc.ListTLSSecret(context.Background(), tt.namespace, "tls-secret")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if summary.Expiry.String() != tt.expected {
+				t.Errorf("expected summary expiry %s, got %s", tt.expected, summary.Expiry)
+			}
+		})
+	}
+}
+
+func TestExpiryThresholdsValidate(t *testing.T) {
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name       string
+		thresholds service.ExpiryThresholds
+		expectErr  bool
+	}{
+		{name: "Should accept a critical duration below the warning", thresholds: service.ExpiryThresholds{Warning: service.Threshold{Duration: 30 * day}, Critical: service.Threshold{Duration: 7 * day}}},
+		{name: "Should reject a critical duration above the warning", thresholds: service.ExpiryThresholds{Warning: service.Threshold{Duration: 7 * day}, Critical: service.Threshold{Duration: 30 * day}}, expectErr: true},
+		{name: "Should reject equal percentages", thresholds: service.ExpiryThresholds{Warning: service.Threshold{Percent: 10}, Critical: service.Threshold{Percent: 10}}, expectErr: true},
+		{name: "Should accept a zero critical threshold", thresholds: service.ExpiryThresholds{Warning: service.Threshold{Percent: 25}}},
+		{name: "Should reject a critical threshold above a zero warning", thresholds: service.ExpiryThresholds{Critical: service.Threshold{Duration: 7 * day}}, expectErr: true},
+		{name: "Should accept thresholds of different kinds", thresholds: service.ExpiryThresholds{Warning: service.Threshold{Percent: 25}, Critical: service.Threshold{Duration: 7 * day}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.thresholds.Validate(); (err != nil) != tt.expectErr {
+				t.Errorf("expected error: %v, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
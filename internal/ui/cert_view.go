@@ -2,9 +2,9 @@ package ui
 
 import (
 	"fmt"
+	"path"
 	"reflect"
 	"strings"
-	"time"
 
 	"github.com/charmbracelet/lipgloss"
 
@@ -38,7 +38,7 @@ func viewFieldsFromStruct(s interface{}) []CertField {
 		switch fv := fieldValue.Interface().(type) {
 		case []string:
 			strVal = strings.Join(fv, ", ")
-		case time.Duration:
+		case service.Duration:
 			strVal = fv.String()
 		case float64:
 			strVal = fmt.Sprintf("%.2f", fv)
@@ -88,3 +88,204 @@ func formatCertificateInfo(ci service.CertificateInfo, t ThemeProvider) string {
 
 	return sb.String()
 }
+
+func formatChainReport(report service.ChainReport, t ThemeProvider) string {
+	var sb strings.Builder
+
+	sb.WriteString(t.SectionHeader().Render("Chain Status"))
+	sb.WriteString("\n")
+	sb.WriteString(renderField(t.Key(), t.Value(), "Status", report.Status))
+	sb.WriteString("\n")
+	sb.WriteString(renderField(t.Key(), t.Value(), "Trusted", fmt.Sprintf("%v", report.Trusted)))
+	sb.WriteString("\n")
+
+	for i, path := range report.Paths {
+		sb.WriteString(renderField(t.Key(), t.Value().MaxWidth(0), fmt.Sprintf("Path %d", i+1), path))
+		sb.WriteString("\n")
+	}
+
+	for _, issue := range report.Issues {
+		sb.WriteString(t.Warning().Render("• " + issue))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// formatPrivateKeyInfo shows the algorithm and encoding of the private key and flags weak parameters.
+func formatPrivateKeyInfo(info service.PrivateKeyInfo, t ThemeProvider) string {
+	var sb strings.Builder
+
+	sb.WriteString(t.SectionHeader().Render("Private Key Info"))
+	sb.WriteString("\n")
+	if info.Error != "" {
+		sb.WriteString(t.Warning().Render("⚠ " + info.Error))
+		sb.WriteString("\n")
+	}
+	for _, f := range viewFieldsFromStruct(info) {
+		if f.Value == "" || f.Value == "0" {
+			continue // unknown for encrypted or unparsable keys
+		}
+		sb.WriteString(renderField(t.Key(), t.Value(), f.Label, f.Value))
+		sb.WriteString("\n")
+	}
+	for _, weakness := range info.Weaknesses {
+		sb.WriteString(t.Warning().Render("⚠ " + weakness))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// formatFindings lists the lint findings of a single certificate, errors are highlighted as warnings.
+func formatFindings(findings []service.Finding, t ThemeProvider) string {
+	var sb strings.Builder
+
+	sb.WriteString(t.SectionHeader().Render("Findings"))
+	sb.WriteString("\n")
+	for _, finding := range findings {
+		line := fmt.Sprintf("• [%s] %s: %s", finding.Severity, finding.ID, finding.Message)
+		if finding.Severity == service.SeverityInfo {
+			sb.WriteString(t.Value().MaxWidth(0).Render(line))
+		} else {
+			sb.WriteString(t.Warning().Render(line))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// formatSection renders the labelled fields of a report struct under a section header.
+func formatSection(title string, section interface{}, t ThemeProvider) string {
+	var sb strings.Builder
+
+	sb.WriteString(t.SectionHeader().Render(title))
+	sb.WriteString("\n")
+	for _, f := range viewFieldsFromStruct(section) {
+		sb.WriteString(renderField(t.Key(), t.Value(), f.Label, f.Value))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+func formatUsedBy(inspection service.TLSSecretInspection, t ThemeProvider) string {
+	var sb strings.Builder
+
+	sb.WriteString(t.SectionHeader().Render("Used By"))
+	sb.WriteString("\n")
+	switch {
+	case inspection.UsedByErr != nil:
+		sb.WriteString(t.Warning().Render(inspection.UsedByErr.Error()))
+		sb.WriteString("\n")
+	case len(inspection.UsedBy) == 0:
+		sb.WriteString(t.Warning().Render("⊘ not referenced by any Ingress, Gateway or Route"))
+		sb.WriteString("\n")
+	}
+	for _, reference := range inspection.UsedBy {
+		sb.WriteString(t.Value().MaxWidth(0).Render("• " + reference))
+		sb.WriteString("\n")
+	}
+	for _, host := range inspection.UncoveredHosts {
+		sb.WriteString(t.Warning().Render("⚠ host not covered by the certificate: " + host))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// formatSource shows the resource and data key the certificates of a scanned resource were read from.
+func formatSource(inspection service.TLSSecretInspection, t ThemeProvider) string {
+	var sb strings.Builder
+
+	sb.WriteString(t.SectionHeader().Render("Source"))
+	sb.WriteString("\n")
+	sb.WriteString(renderField(t.Key(), t.Value(), "Resource", inspection.Kind+" "+path.Join(inspection.Namespace, inspection.Name)))
+	sb.WriteString("\n")
+	sb.WriteString(renderField(t.Key(), t.Value(), "Key", inspection.CertKey))
+	sb.WriteString("\n\n")
+
+	return sb.String()
+}
+
+// bundlePages renders every certificate of ca.crt and the other PEM keys as its own page.
+func bundlePages(bundles []service.PEMBundle, t ThemeProvider) ([]string, []*service.CertificateInfo) {
+	var pages []string
+	var certs []*service.CertificateInfo
+	for _, bundle := range bundles {
+		if bundle.Error != "" {
+			pages = append(pages, t.SectionHeader().Render(bundle.Key)+"\n"+t.Warning().Render("⚠ "+bundle.Error))
+			certs = append(certs, nil)
+			continue
+		}
+		for i, cert := range bundle.Certificates {
+			header := t.SectionHeader().Render(fmt.Sprintf("%s · certificate %d of %d", bundle.Key, i+1, len(bundle.Certificates)))
+			pages = append(pages, header+"\n\n"+formatCertificateInfo(cert, t))
+			certs = append(certs, &bundle.Certificates[i])
+		}
+	}
+	return pages, certs
+}
+
+// formatProbeReport shows whether the live endpoint serves the chain of the inspected secret.
+func formatProbeReport(target string, report service.ProbeReport, err error, t ThemeProvider) string {
+	var sb strings.Builder
+
+	sb.WriteString(t.SectionHeader().Render("Live Endpoint"))
+	sb.WriteString("\n")
+	sb.WriteString(renderField(t.Key(), t.Value(), "Target", target))
+	sb.WriteString("\n")
+	switch {
+	case err != nil:
+		sb.WriteString(t.Warning().Render("⚠ " + err.Error()))
+		sb.WriteString("\n")
+	case report.Status == service.ProbeMatch:
+		sb.WriteString(renderField(t.Key(), t.Value(), "Status", report.Status))
+		sb.WriteString("\n")
+	default:
+		sb.WriteString(t.Warning().Render("⚠ Status: " + report.Status))
+		sb.WriteString("\n")
+	}
+	for _, diff := range report.Fingerprints {
+		if diff.Match() {
+			continue
+		}
+		served, stored := diff.Fingerprints()
+		sb.WriteString(renderField(t.Key(), t.Value().MaxWidth(0), fmt.Sprintf("Served #%d", diff.Position+1), served))
+		sb.WriteString("\n")
+		sb.WriteString(renderField(t.Key(), t.Value().MaxWidth(0), fmt.Sprintf("Stored #%d", diff.Position+1), stored))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// probePages renders every certificate served by the live endpoint as its own page.
+func probePages(report service.ProbeReport, t ThemeProvider) ([]string, []*service.CertificateInfo) {
+	var pages []string
+	var certs []*service.CertificateInfo
+	for i, cert := range report.Certificates {
+		header := t.SectionHeader().Render(fmt.Sprintf("served by %s · certificate %d of %d", report.Target, i+1, len(report.Certificates)))
+		pages = append(pages, header+"\n\n"+formatCertificateInfo(cert, t))
+		certs = append(certs, &report.Certificates[i])
+	}
+	return pages, certs
+}
+
+// findingsOf returns the findings of the certificate at the given chain position.
+func findingsOf(findings []service.Finding, position int) []service.Finding {
+	var matching []service.Finding
+	for _, finding := range findings {
+		if finding.Position == position {
+			matching = append(matching, finding)
+		}
+	}
+	return matching
+}
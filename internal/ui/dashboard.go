@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+const dashboardBarWidth = 30
+
+type dashboardSection struct {
+	title     string
+	entries   []service.DashboardEntry
+	histogram bool
+	column    int
+}
+
+// dashboardModel renders the cluster-wide overview, every entry can be selected to drill
+// down into the secrets it counts.
+type dashboardModel struct {
+	dashboard service.Dashboard
+	cursor    int
+	theme     ThemeProvider
+}
+
+func newDashboardModel(theme ThemeProvider) dashboardModel {
+	return dashboardModel{
+		dashboard: service.NewDashboard(nil),
+		theme:     theme,
+	}
+}
+
+func (d *dashboardModel) SetSummaries(summaries []service.TLSSecretSummary) {
+	d.dashboard = service.NewDashboard(summaries)
+	d.cursor = min(d.cursor, len(d.entries())-1)
+}
+
+// sections are laid out in two columns, the cursor walks the sections in this order. The
+// clusters are only shown when several clusters are inspected.
+func (d dashboardModel) sections() []dashboardSection {
+	sections := []dashboardSection{
+		{title: "Status", entries: append([]service.DashboardEntry{d.dashboard.Total}, d.dashboard.Statuses...)},
+		{title: "Findings", entries: []service.DashboardEntry{d.dashboard.SelfSigned, d.dashboard.KeyMismatch, d.dashboard.Unused}},
+	}
+	if len(d.dashboard.Clusters) > 0 {
+		sections = append(sections, dashboardSection{title: "Clusters", entries: d.dashboard.Clusters})
+	}
+	return append(sections,
+		dashboardSection{title: "Expiring in the next 90 days", entries: d.dashboard.Expiring, histogram: true, column: 1},
+		dashboardSection{title: "Top issuers", entries: d.dashboard.TopIssuers, column: 1},
+	)
+}
+
+func (d dashboardModel) entries() []service.DashboardEntry {
+	var entries []service.DashboardEntry
+	for _, section := range d.sections() {
+		entries = append(entries, section.entries...)
+	}
+	return entries
+}
+
+func (d *dashboardModel) CursorUp() {
+	if d.cursor > 0 {
+		d.cursor--
+	}
+}
+
+func (d *dashboardModel) CursorDown() {
+	if d.cursor < len(d.entries())-1 {
+		d.cursor++
+	}
+}
+
+// SelectedFilter returns the entry under the cursor, nil if it counts all secrets.
+func (d dashboardModel) SelectedFilter() *service.DashboardEntry {
+	if d.cursor == 0 {
+		return nil
+	}
+	entry := d.entries()[d.cursor]
+	return &entry
+}
+
+func (d dashboardModel) View() string {
+	title := d.theme.SectionHeader().Render(fmt.Sprintf("TLS Certificate Dashboard (%d secrets)", d.dashboard.Total.Count))
+
+	var columns [2]strings.Builder
+	index := 0
+	for _, section := range d.sections() {
+		column := &columns[section.column]
+		column.WriteString("\n" + d.theme.SectionHeader().Render(section.title) + "\n")
+		if len(section.entries) == 0 {
+			column.WriteString(d.theme.Value().Render("none") + "\n")
+		}
+
+		maxCount := 0
+		for _, entry := range section.entries {
+			maxCount = max(maxCount, entry.Count)
+		}
+
+		for _, entry := range section.entries {
+			keyStyle := d.theme.Key()
+			if section.title == "Status" && index != 0 {
+				keyStyle = keyStyle.Foreground(d.theme.ExpiryBadge(entry.Label).GetForeground())
+			}
+			label := keyStyle.Render(truncate(entry.Label, keyStyle.GetWidth()))
+
+			value := fmt.Sprintf("%d", entry.Count)
+			if section.histogram && maxCount > 0 {
+				value = strings.Repeat("█", entry.Count*dashboardBarWidth/maxCount) + " " + value
+			}
+
+			cursor := "  "
+			if index == d.cursor {
+				cursor = d.theme.Selected().Render("▶ ")
+			}
+			column.WriteString(cursor + label + d.theme.Value().MaxWidth(0).Render(value) + "\n")
+			index++
+		}
+	}
+
+	left := lipgloss.NewStyle().PaddingRight(4).Render(columns[0].String())
+	return lipgloss.JoinVertical(lipgloss.Left, title, lipgloss.JoinHorizontal(lipgloss.Top, left, columns[1].String()))
+}
+
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if width <= 0 || len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
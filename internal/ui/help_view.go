@@ -5,9 +5,10 @@ import (
 )
 
 type HelpViewModel struct {
-	pane  Pane
-	theme ThemeProvider
-	width int
+	pane   Pane
+	theme  ThemeProvider
+	width  int
+	status string
 }
 
 func NewHelpViewModel(p Pane, tp ThemeProvider) HelpViewModel {
@@ -26,8 +27,13 @@ var baseKeyHints = []keyHint{
 	{"tab", "switch pane"},
 	{"p", "switch pane"},
 	{"r", "toggle raw"},
+	{"i", "inspect again"},
 	{"c", "copy cert"},
 	{"C", "copy key"},
+	{"e", "export"},
+	{"d", "dashboard"},
+	{"n", "namespace"},
+	{"x", "context"},
 	{"q", "quit"},
 }
 
@@ -35,14 +41,34 @@ var leftPaneKeyHints = []keyHint{
 	{"↑/↓", "navigate"},
 	{"←/→", "switch list page"},
 	{"/", "filter"},
+	{"s", "sort"},
 }
 
 var rightPaneKeyHints = []keyHint{
 	{"↑/↓", "scroll"},
 	{"←/→", "switch cert page"},
+	{"f", "copy fingerprint"},
 	{"enter", "select"},
 }
 
+var dashboardKeyHints = []keyHint{
+	{"↑/↓", "navigate"},
+	{"enter", "show secrets"},
+	{"d", "secrets list"},
+	{"u", "refresh"},
+	{"e", "export"},
+	{"n", "namespace"},
+	{"x", "context"},
+	{"q", "quit"},
+}
+
+var pickerKeyHints = []keyHint{
+	{"↑/↓", "navigate"},
+	{"/", "filter"},
+	{"enter", "switch"},
+	{"esc", "cancel"},
+}
+
 const separator = "  •  "
 
 func (h HelpViewModel) View() string {
@@ -52,9 +78,18 @@ func (h HelpViewModel) View() string {
 		keyHints = append(leftPaneKeyHints, baseKeyHints...)
 	case RightPane:
 		keyHints = append(rightPaneKeyHints, baseKeyHints...)
+	case DashboardPane:
+		keyHints = dashboardKeyHints
+	case PickerPane:
+		keyHints = pickerKeyHints
+	}
+
+	hints := formatKeyHints(keyHints)
+	if h.status != "" {
+		hints = h.status + separator + hints
 	}
 
-	return h.theme.Help(h.width).Render(formatKeyHints(keyHints))
+	return h.theme.Help(h.width).Render(hints)
 }
 
 func formatKeyHints(hints []keyHint) string {
@@ -72,3 +107,7 @@ func (h *HelpViewModel) SetPane(p Pane) {
 func (h *HelpViewModel) SetWidth(width int) {
 	h.width = width
 }
+
+func (h *HelpViewModel) SetStatus(status string) {
+	h.status = status
+}
@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"context"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/codechamp1/certlens/internal/export"
+	"github.com/codechamp1/certlens/internal/service"
+)
+
+// Switcher lists and opens the targets the TUI can switch to without restarting.
+type Switcher interface {
+	Contexts() ([]string, error)
+	Namespaces(ctx context.Context, kubeContext string) ([]string, error)
+	Service(context string) (service.SecretsService, error)
+}
+
+type pickerKind int
+
+const (
+	namespacePicker pickerKind = iota
+	contextPicker
+	identifierPicker
+	exportPicker
+)
+
+const allNamespaces = "All namespaces"
+
+type pickerLoadedMsg struct {
+	kind    pickerKind
+	options []string
+}
+
+type pickerSelectedMsg struct {
+	kind   pickerKind
+	label  string
+	option string
+}
+
+type pickerItem struct {
+	label, value string
+	description  string
+	current      bool
+}
+
+func (p pickerItem) Title() string {
+	if p.current {
+		return "● " + p.label
+	}
+	return p.label
+}
+func (p pickerItem) Description() string { return p.description }
+func (p pickerItem) FilterValue() string { return p.label }
+
+// pickerModel is the overlay listing the namespaces or the contexts to switch to, it returns
+// to the pane it was opened from.
+type pickerModel struct {
+	kind     pickerKind
+	list     list.Model
+	previous Pane
+}
+
+func newPickerModel(kind pickerKind, options []string, current string, previous Pane) pickerModel {
+	var items []list.Item
+	if kind == namespacePicker {
+		items = append(items, pickerItem{label: allNamespaces, current: current == ""})
+	}
+	for _, option := range options {
+		items = append(items, pickerItem{label: option, value: option, current: option == current})
+	}
+
+	delegate := list.NewDefaultDelegate()
+	delegate.ShowDescription = false
+	delegate.SetSpacing(0)
+
+	l := list.New(items, delegate, 40, 20)
+	l.SetShowHelp(false)
+	l.Title = "Switch namespace"
+	if kind == contextPicker {
+		l.Title = "Switch context"
+	}
+	for i, item := range items {
+		if item.(pickerItem).current {
+			l.Select(i)
+		}
+	}
+
+	return pickerModel{kind: kind, list: l, previous: previous}
+}
+
+// newIdentifierPickerModel lists the fingerprints of a certificate with their values, the
+// picked value is copied.
+func newIdentifierPickerModel(identifiers []CertField, previous Pane) pickerModel {
+	items := make([]list.Item, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		items = append(items, pickerItem{label: identifier.Label, value: identifier.Value, description: identifier.Value})
+	}
+
+	l := list.New(items, list.NewDefaultDelegate(), 40, 20)
+	l.SetShowHelp(false)
+	l.Title = "Copy fingerprint"
+
+	return pickerModel{kind: identifierPicker, list: l, previous: previous}
+}
+
+// newExportPickerModel lists the export formats, the inventory is written to dir in the
+// picked one.
+func newExportPickerModel(formats []export.Format, current export.Format, dir string, previous Pane) pickerModel {
+	items := make([]list.Item, 0, len(formats))
+	for _, format := range formats {
+		items = append(items, pickerItem{label: string(format), value: string(format), current: format == current})
+	}
+
+	delegate := list.NewDefaultDelegate()
+	delegate.ShowDescription = false
+	delegate.SetSpacing(0)
+
+	l := list.New(items, delegate, 40, 20)
+	l.SetShowHelp(false)
+	l.Title = "Export to " + dir
+	for i, item := range items {
+		if item.(pickerItem).current {
+			l.Select(i)
+		}
+	}
+
+	return pickerModel{kind: exportPicker, list: l, previous: previous}
+}
+
+func (p *pickerModel) SetSize(width, height int) {
+	p.list.SetSize(width, height)
+}
+
+// Update handles the keys of the overlay, the returned command reports the choice. Closing
+// without a choice is reported by done.
+func (p *pickerModel) Update(msg tea.Msg) (cmd tea.Cmd, done bool) {
+	if key, ok := msg.(tea.KeyMsg); ok && p.list.FilterState() != list.Filtering {
+		switch key.String() {
+		case "esc", "q":
+			return nil, true
+		case "enter":
+			item, ok := p.list.SelectedItem().(pickerItem)
+			if !ok {
+				return nil, true
+			}
+			kind := p.kind
+			return func() tea.Msg { return pickerSelectedMsg{kind: kind, label: item.label, option: item.value} }, true
+		}
+	}
+
+	p.list, cmd = p.list.Update(msg)
+	return cmd, false
+}
+
+func (p pickerModel) View() string {
+	return p.list.View()
+}
+
+func loadPickerCmd(m Model, kind pickerKind) tea.Cmd {
+	return func() tea.Msg {
+		if m.switcher == nil {
+			return statusMsg{"Switching is not available for local files"}
+		}
+
+		var options []string
+		var err error
+		switch kind {
+		case contextPicker:
+			options, err = m.switcher.Contexts()
+		default:
+			options, err = m.switcher.Namespaces(context.Background(), m.context)
+		}
+		if err != nil {
+			return statusMsg{"Switching unavailable: " + err.Error()}
+		}
+		return pickerLoadedMsg{kind: kind, options: options}
+	}
+}
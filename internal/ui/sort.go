@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+type sortMode int
+
+const (
+	sortByName sortMode = iota
+	sortByNamespace
+	sortByExpiry
+	sortBySeverity
+)
+
+var sortModeStrings = map[sortMode]string{
+	sortByName:      "name",
+	sortByNamespace: "namespace",
+	sortByExpiry:    "soonest expiry",
+	sortBySeverity:  "status severity",
+}
+
+func (s sortMode) String() string {
+	return sortModeStrings[s]
+}
+
+func (s sortMode) next() sortMode {
+	return (s + 1) % sortMode(len(sortModeStrings))
+}
+
+// sortItems orders the secret items in place, ties are broken by namespace and name.
+func sortItems(items []list.Item, mode sortMode) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, okA := items[i].(secretItem)
+		b, okB := items[j].(secretItem)
+		if !okA || !okB {
+			return false
+		}
+
+		switch mode {
+		case sortByExpiry:
+			if a.summary.Expiry.Known() != b.summary.Expiry.Known() {
+				return a.summary.Expiry.Known() // unparsable certificates last
+			}
+			if a.summary.TimeUntilExpiry != b.summary.TimeUntilExpiry {
+				return a.summary.TimeUntilExpiry < b.summary.TimeUntilExpiry
+			}
+		case sortBySeverity:
+			if a.summary.Expiry.Severity() != b.summary.Expiry.Severity() {
+				return a.summary.Expiry.Severity() > b.summary.Expiry.Severity()
+			}
+		case sortByNamespace:
+			if a.namespace != b.namespace {
+				return a.namespace < b.namespace
+			}
+		}
+
+		if mode != sortByName && a.namespace != b.namespace {
+			return a.namespace < b.namespace
+		}
+		if a.name != b.name {
+			return strings.ToLower(a.name) < strings.ToLower(b.name)
+		}
+		return a.namespace < b.namespace
+	})
+}
@@ -2,6 +2,8 @@ package ui
 
 import (
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/codechamp1/certlens/internal/service"
 )
 
 type ThemeProvider interface {
@@ -12,6 +14,10 @@ type ThemeProvider interface {
 	Key() lipgloss.Style
 	Value() lipgloss.Style
 	Help(width int) lipgloss.Style
+	Warning() lipgloss.Style
+	ExpiryBadge(status string) lipgloss.Style
+	Selected() lipgloss.Style
+	KindBadge() lipgloss.Style
 }
 
 type Theme struct {
@@ -20,6 +26,8 @@ type Theme struct {
 	sectionHeader lipgloss.Style
 	key           lipgloss.Style
 	value         lipgloss.Style
+	warning       lipgloss.Style
+	selected      lipgloss.Style
 }
 
 var Default = Theme{
@@ -49,6 +57,13 @@ var Default = Theme{
 		MaxWidth(50).
 		PaddingLeft(1), // Same dark gray as the main text for values
 
+	warning: lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#ff5555")),
+
+	selected: lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#00BFFF")),
 }
 
 func (t Theme) DocStyle() lipgloss.Style {
@@ -89,3 +104,31 @@ func (t Theme) Help(width int) lipgloss.Style {
 	return lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#888")).MarginLeft(1).Width(width)
 }
+
+func (t Theme) Warning() lipgloss.Style {
+	return t.warning
+}
+
+func (t Theme) Selected() lipgloss.Style {
+	return t.selected
+}
+
+// KindBadge marks the Opaque secrets and ConfigMaps found by the certificate scanner.
+func (t Theme) KindBadge() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#5fafff")).Bold(true)
+}
+
+var expiryBadgeColors = map[string]lipgloss.Color{
+	service.StatusOK.String():       lipgloss.Color("#00FF00"),
+	service.StatusWarning.String():  lipgloss.Color("#FFA500"),
+	service.StatusCritical.String(): lipgloss.Color("#ff5555"),
+	service.StatusExpired.String():  lipgloss.Color("#ff0000"),
+}
+
+func (t Theme) ExpiryBadge(status string) lipgloss.Style {
+	color, ok := expiryBadgeColors[status]
+	if !ok {
+		color = lipgloss.Color("#888")
+	}
+	return lipgloss.NewStyle().Foreground(color).Bold(true)
+}
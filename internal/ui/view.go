@@ -1,7 +1,12 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/atotto/clipboard"
@@ -11,7 +16,10 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
+	"github.com/codechamp1/certlens/internal/domains"
+	"github.com/codechamp1/certlens/internal/export"
 	"github.com/codechamp1/certlens/internal/service"
 )
 
@@ -20,17 +28,42 @@ type Pane int
 const (
 	LeftPane Pane = iota
 	RightPane
+	DashboardPane
+	PickerPane
 )
 
 type secretsLoadedMsg struct {
-	secrets []list.Item
+	tag     int
+	secrets []secretItem
+}
+
+// secretsPageMsg is a page of a streamed listing, the last message is done and carries the
+// listing error if any. Pages of a replaced listing are drained and dropped.
+type secretsPageMsg struct {
+	tag   int
+	items []secretItem
+	done  bool
+	err   error
+	pages <-chan secretsPageMsg
 }
 
 type inspectTLSSecretMsg struct {
 	tag int
 }
 
-type loadingStartedMsg struct{}
+type inspectedTLSSecretMsg struct {
+	tag   int
+	pages []string
+	certs []*service.CertificateInfo
+	err   error
+}
+
+// probedTLSSecretMsg is the live endpoint compared with the inspection of the same tag.
+type probedTLSSecretMsg struct {
+	tag    int
+	report service.ProbeReport
+	err    error
+}
 
 type loadSecretsMsg struct{}
 
@@ -38,8 +71,31 @@ type copyMsg struct {
 	key bool
 }
 
+// copiedMsg is sent once the certificate or key was written to the clipboard, or failed with err.
+type copiedMsg struct {
+	what string
+	err  error
+}
+
 type switchCertViewMsg struct{}
 
+type exportMsg struct{}
+
+type statusMsg struct{ text string }
+
+// watchStartedMsg is sent once the watch of watchCtx synced, or failed with err. The events
+// of the clusters that synced are kept with a domains.PartialError.
+type watchStartedMsg struct {
+	watchCtx context.Context
+	events   <-chan service.TLSSecretEvent
+	err      error
+}
+
+type secretEventMsg struct {
+	event  service.TLSSecretEvent
+	events <-chan service.TLSSecretEvent // dropped once the watch was replaced by a switch
+}
+
 type switchPaneMsg struct{}
 
 type errorMsg struct{ err error }
@@ -50,28 +106,119 @@ type secretDelegate struct {
 
 type secretItem struct {
 	name      string
-	namespace string
+	namespace string // qualified with the cluster when several clusters are inspected
+	ref       string // name, or kind/name of resources found by the scanner
+	summary   service.TLSSecretSummary
+	changed   bool
+	theme     ThemeProvider
+}
+
+func newSecretItem(summary service.TLSSecretSummary, theme ThemeProvider) secretItem {
+	return secretItem{
+		name:      summary.Name,
+		namespace: summary.QualifiedNamespace(),
+		ref:       summary.Ref(),
+		summary:   summary,
+		theme:     theme,
+	}
+}
+
+func (s secretItem) Title() string {
+	if s.changed {
+		return "● " + s.name
+	}
+	return s.name
+}
+func (s secretItem) Description() string {
+	desc := "Namespace: " + s.summary.Namespace
+	if s.summary.Namespace == "" {
+		desc = "Cluster-scoped"
+	}
+	if s.summary.Cluster != "" {
+		desc = "Cluster: " + s.summary.Cluster + "  " + desc
+	}
+	if s.summary.Kind != "" {
+		desc = s.theme.KindBadge().Render("["+s.summary.Kind+"]") + " " + desc
+	}
+	if keyPair := s.summary.KeyPair; keyPair == service.KeyPairMismatch || keyPair == service.KeyPairInvalid {
+		desc += "  ⚠ key: " + keyPair.String()
+	}
+	if s.summary.Unused {
+		desc += "  ⊘ unused"
+	}
+	return desc + "  " + s.expiryBadge()
+}
+func (s secretItem) FilterValue() string {
+	if s.summary.Cluster == "" {
+		return s.name
+	}
+	return s.summary.Cluster + " " + s.name // filter by cluster as well
 }
 
-func (s secretItem) Title() string       { return s.name }
-func (s secretItem) Description() string { return "Namespace: " + s.namespace }
-func (s secretItem) FilterValue() string { return s.name }
+func (s secretItem) expiryBadge() string {
+	expiry := s.summary.Expiry
+	text := expiry.String()
+	if expiry.Known() {
+		text += " · " + formatDays(s.summary.TimeUntilExpiry)
+	}
+	return s.theme.ExpiryBadge(expiry.String()).Render(text)
+}
+
+func formatDays(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	if d < 0 {
+		return fmt.Sprintf("%dd ago", -days)
+	}
+	return fmt.Sprintf("%dd", days)
+}
 
 const debounceDuration = 100 * time.Millisecond
 
+// copyTimeout bounds fetching a secret for the clipboard as a whole, a copy must not hang
+// around until it lands in the clipboard long after it was asked for.
+const copyTimeout = 30 * time.Second
+
 type Model struct {
 	//Services & configuration
 	secretsService service.SecretsService
 	namespace      string
 	name           string
+	watch          bool
+	probeTarget    string                // endpoint compared with probeSecret, if set
+	probeSecret    domains.K8SResourceID // the secret lensed at startup, which probeTarget serves
 	theme          ThemeProvider
+	switcher       Switcher // nil when the namespace and context can not be switched
+	context        string
+	exportDir      string        // directory the inventory is exported to
+	exportFormat   export.Format // preselected in the export picker, the last one picked
+
+	// Live updates, the watch starts alongside the listing. Events received while a listing
+	// runs are applied on top of it once it completed.
+	watchCtx      context.Context
+	stopWatch     context.CancelFunc
+	watchEvents   <-chan service.TLSSecretEvent
+	listing       bool
+	pendingEvents []service.TLSSecretEvent
+
+	// Calls in flight, cancelled when they are replaced
+	cancelLoad    context.CancelFunc
+	cancelInspect context.CancelFunc
+	cancelCopy    context.CancelFunc
+	cancelExport  context.CancelFunc
 
 	debounceTag int
+	loadTag     int // identifies the latest listing, see secretsPageMsg
+	loadedPages int
 
 	// TLS Secret Data
+	secrets        []secretItem
 	selectedSecret *secretItem
 	secretsList    list.Model
+	sortMode       sortMode
+	listFilter     *service.DashboardEntry
+	dashboard      dashboardModel
 	certViewPages  []string
+	certViewCerts  []*service.CertificateInfo // certificate of each page, nil for raw and error pages
 	certPaginator  paginator.Model
 
 	// Ui elements
@@ -84,30 +231,75 @@ type Model struct {
 	spinner           spinner.Model
 	inspectedViewport viewport.Model
 	uiLayout          uiLayout
+	picker            pickerModel
 }
 
-func NewModel(svc service.SecretsService, namespace, name string) (Model, error) {
+func NewModel(svc service.SecretsService, namespace, name string, watch bool, probeTarget string) (Model, error) {
 	var items []list.Item
 	secretsList := list.New(items, newSecretDelegate(), 50, 20)
-	secretsList.Title = "Select a TLS Secret"
 	secretsList.SetShowHelp(false)
-	defaultPane := LeftPane
-	return Model{
+	// the dashboard is the first screen, unless a single secret is lensed
+	defaultPane := DashboardPane
+	if name != "" {
+		defaultPane = LeftPane
+	}
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	m := Model{
+		watchCtx:          watchCtx,
+		stopWatch:         stopWatch,
+		cancelLoad:        func() {},
+		cancelInspect:     func() {},
+		cancelCopy:        func() {},
+		cancelExport:      func() {},
 		certPaginator:     paginator.New(),
 		inspectedViewport: viewport.New(50, 20), // Will be updated later,
 		name:              name,
 		namespace:         namespace,
+		watch:             watch,
+		probeTarget:       probeTarget,
+		probeSecret:       domains.K8SResourceID{Name: name, Namespace: namespace},
+		exportDir:         ".",
+		exportFormat:      export.JSON,
 		secretsService:    svc,
 		secretsList:       secretsList,
 		selectedPane:      defaultPane,
 		spinner:           spinner.New(),
 		theme:             Default,
 		helpView:          NewHelpViewModel(defaultPane, Default),
-	}, nil
+		dashboard:         newDashboardModel(Default),
+	}
+	m.secretsList.Title = m.listTitle()
+	return m, nil
+}
+
+// WithSwitcher lets the user switch to another namespace or context from the TUI, context is
+// the one inspected at startup.
+func (m Model) WithSwitcher(switcher Switcher, context string) Model {
+	m.switcher = switcher
+	m.context = context
+	m.secretsList.Title = m.listTitle()
+	return m
+}
+
+// WithExport sets the directory the inventory exported with e is written to and the format
+// preselected in the export picker.
+func (m Model) WithExport(dir string, format export.Format) Model {
+	m.exportDir = dir
+	m.exportFormat = format
+	return m
 }
 
 func (m Model) Init() tea.Cmd {
-	return func() tea.Msg { return loadSecretsMsg{} }
+	return m.startListing()
+}
+
+// startListing lists the secrets and, if live updates are enabled, starts the watch alongside.
+func (m Model) startListing() tea.Cmd {
+	load := func() tea.Msg { return loadSecretsMsg{} }
+	if m.watch {
+		return tea.Batch(load, startWatchCmd(m))
+	}
+	return load
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -115,12 +307,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if m.errorModalMsg != "" {
-			return m, tea.Quit
+			return m, m.quit()
 		}
 
 		keyStr := msg.String()
 		if keyStr == "ctrl+c" {
-			return m, tea.Quit
+			return m, m.quit()
+		}
+
+		if m.selectedPane == PickerPane {
+			cmd, done := m.picker.Update(msg)
+			if done {
+				m.selectedPane = m.picker.previous
+				m.helpView.SetPane(m.selectedPane)
+			}
+			return m, cmd
+		}
+
+		if m.selectedPane == DashboardPane {
+			return m, tea.Batch(m.updateDashboard(keyStr), m.syncSelection())
 		}
 
 		if m.selectedPane == RightPane {
@@ -131,45 +336,123 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "right":
 				m.certPaginator.NextPage()
 				m.inspectedViewport.SetContent(m.certViewPages[m.certPaginator.Page] + "\n\n" + m.certPaginator.View())
+			case "f":
+				m.openIdentifierPicker()
+				return m, nil
 			}
 		}
 		if m.secretsList.FilterState() != list.Filtering {
 			switch keyStr {
 			case "q", "ctrl+c":
-				return m, tea.Quit
+				return m, m.quit()
 			case "u":
 				cmds = append(cmds, func() tea.Msg { return loadSecretsMsg{} })
 			case "tab", "p":
 				cmds = append(cmds, func() tea.Msg { return switchPaneMsg{} })
 			case "r":
 				cmds = append(cmds, func() tea.Msg { return switchCertViewMsg{} })
+			case "i":
+				cmds = append(cmds, func() tea.Msg { return inspectTLSSecretMsg{tag: m.debounceTag} })
 			case "c":
 				cmds = append(cmds, func() tea.Msg { return copyMsg{} })
 			case "C":
 				cmds = append(cmds, func() tea.Msg { return copyMsg{key: true} })
+			case "e":
+				cmds = append(cmds, func() tea.Msg { return exportMsg{} })
+			case "s":
+				m.sortMode = m.sortMode.next()
+				cmds = append(cmds, m.refreshList())
+			case "d":
+				m.selectedPane = DashboardPane
+				m.helpView.SetPane(m.selectedPane)
+			case "n":
+				cmds = append(cmds, loadPickerCmd(m, namespacePicker))
+			case "x":
+				cmds = append(cmds, loadPickerCmd(m, contextPicker))
 			}
 		}
 
 	case tea.WindowSizeMsg:
 		m.updateLayout(msg.Width, msg.Height)
 	case copyMsg:
-		var copyData string
-		tlsCert, tlsKey, err := m.secretsService.RawInspectTLSSecret(m.selectedSecret.namespace, m.selectedSecret.name)
-		if err != nil {
-			m.errorModalMsg = fmt.Sprintf("Error copying secret: %v", err)
+		if m.selectedSecret == nil {
+			break
 		}
+		m.cancelCopy()
+		var ctx context.Context
+		ctx, m.cancelCopy = context.WithTimeout(context.Background(), copyTimeout)
+		cmds = append(cmds, copySecretCmd(ctx, m, msg.key))
+	case copiedMsg:
 		switch {
-		case msg.key:
-			copyData = tlsKey
+		case errors.Is(msg.err, context.Canceled):
+		case timedOut(msg.err):
+			m.helpView.SetStatus("Copying timed out, press c or C to retry")
+		case msg.err != nil:
+			m.reportError("Error copying secret", msg.err)
 		default:
-			copyData = tlsCert
+			m.helpView.SetStatus(fmt.Sprintf("Copied the %s to the clipboard", msg.what))
+		}
+	case exportMsg:
+		m.openExportPicker()
+	case statusMsg:
+		m.helpView.SetStatus(msg.text)
+	case watchStartedMsg:
+		if msg.watchCtx != m.watchCtx {
+			break // the watch of a previous target
 		}
-		if err := clipboard.WriteAll(copyData); err != nil {
-			m.errorModalMsg = fmt.Sprintf("Error copying secret: %v", err)
+		var partial *domains.PartialError
+		switch {
+		case errors.As(msg.err, &partial):
+			m.helpView.SetStatus(fmt.Sprintf("Live updates unavailable for some clusters: %v", partial))
+		case msg.err != nil:
+			m.helpView.SetStatus(fmt.Sprintf("Live updates unavailable: %v", msg.err))
+		}
+		if msg.events != nil {
+			m.watchEvents = msg.events
+			cmds = append(cmds, waitForSecretEventCmd(msg.events))
 		}
+	case secretEventMsg:
+		if msg.events == m.watchEvents {
+			if m.listing {
+				m.pendingEvents = append(m.pendingEvents, msg.event)
+			} else {
+				cmds = append(cmds, m.applySecretEvent(msg.event))
+			}
+			cmds = append(cmds, waitForSecretEventCmd(m.watchEvents))
+		}
+	case pickerLoadedMsg:
+		m.openPicker(msg)
+	case pickerSelectedMsg:
+		if msg.kind == identifierPicker {
+			m.copyIdentifier(msg)
+			break
+		}
+		if msg.kind == exportPicker {
+			m.exportFormat = export.Format(msg.option)
+			m.helpView.SetStatus("Exporting inventory...")
+			m.cancelExport()
+			var ctx context.Context
+			ctx, m.cancelExport = context.WithCancel(context.Background())
+			cmds = append(cmds, exportInventoryCmd(ctx, m, m.exportFormat))
+			break
+		}
+		cmds = append(cmds, m.switchTarget(msg))
 	case secretsLoadedMsg:
-		m.secretsList.SetItems(msg.secrets)
-		m.loading = false
+		if msg.tag == m.loadTag {
+			m.secrets = msg.secrets
+			cmds = append(cmds, m.refreshList(), m.finishListing())
+			m.loading = false
+		}
+	case secretsPageMsg:
+		if !msg.done {
+			cmds = append(cmds, waitForSecretsPageCmd(msg.pages))
+		}
+		if msg.tag == m.loadTag {
+			cmds = append(cmds, m.applySecretsPage(msg))
+			if msg.done {
+				cmds = append(cmds, m.finishListing())
+			}
+		}
 	case switchCertViewMsg:
 		m.showRaw = !m.showRaw
 		cmds = append(cmds, func() tea.Msg { return inspectTLSSecretMsg{tag: m.debounceTag} })
@@ -177,17 +460,33 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.selectedPane = nextPane(m.selectedPane)
 		m.helpView.SetPane(m.selectedPane)
 	case loadSecretsMsg:
-		cmds = append(cmds, loadSecretsCmd(m))
-	case loadingStartedMsg:
+		m.loadTag++
+		m.loadedPages = 0
 		m.loading = true
-		cmds = append(cmds, m.spinner.Tick)
+		m.listing = true
+		m.cancelLoad()
+		var ctx context.Context
+		ctx, m.cancelLoad = context.WithCancel(context.Background())
+		cmds = append(cmds, m.spinner.Tick, loadSecretsCmd(ctx, m))
 	case inspectTLSSecretMsg:
+		if msg.tag == m.debounceTag && m.selectedSecret != nil {
+			m.cancelInspect()
+			var ctx context.Context
+			ctx, m.cancelInspect = context.WithCancel(context.Background())
+			cmds = append(cmds, inspectTLSSecretCmd(ctx, m, msg.tag))
+		}
+	case inspectedTLSSecretMsg:
+		if msg.tag == m.debounceTag {
+			cmds = append(cmds, m.applyInspection(msg))
+		}
+	case probedTLSSecretMsg:
 		if msg.tag == m.debounceTag {
-			m.handleInspectTLSSecretMsg()
+			m.applyProbe(msg)
 		}
 	case errorMsg:
 		m.loading = false
-		m.errorModalMsg = fmt.Sprintf("Error: %v", msg.err)
+		m.reportError("Error", msg.err)
+		cmds = append(cmds, m.finishListing())
 	}
 
 	if m.loading {
@@ -204,29 +503,305 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			var vpCmd tea.Cmd
 			m.inspectedViewport, vpCmd = m.inspectedViewport.Update(msg)
 			cmds = append(cmds, vpCmd)
+		case PickerPane:
+			pickerCmd, _ := m.picker.Update(msg)
+			cmds = append(cmds, pickerCmd)
 		}
 	}
 
+	cmds = append(cmds, m.syncSelection())
+
+	return m, tea.Batch(cmds...)
+}
+
+// syncSelection schedules a debounced inspection when the selected list item changed.
+func (m *Model) syncSelection() tea.Cmd {
 	if sel := m.secretsList.SelectedItem(); sel != nil {
 		if item, ok := sel.(secretItem); ok {
-			if m.selectedSecret == nil || item.name != m.selectedSecret.name || item.namespace != m.selectedSecret.namespace {
+			if m.selectedSecret == nil || item.summary.K8SResourceID != m.selectedSecret.summary.K8SResourceID {
+				m.cancelInspect() // the previous selection is no longer shown
 				m.selectedSecret = &item
 				m.debounceTag++
-				cmds = append(cmds, tea.Tick(debounceDuration, func(t time.Time) tea.Msg { return inspectTLSSecretMsg{tag: m.debounceTag} }))
+				tag := m.debounceTag
+				return tea.Tick(debounceDuration, func(t time.Time) tea.Msg { return inspectTLSSecretMsg{tag: tag} })
 			}
 		}
 	}
+	return nil
+}
 
-	return m, tea.Batch(cmds...)
+// quit stops the watch and the export in flight and quits.
+func (m Model) quit() tea.Cmd {
+	m.stopWatch()
+	m.cancelExport()
+	return tea.Quit
 }
 
-func (m *Model) handleInspectTLSSecretMsg() {
-	data, err := m.inspectedTLSSecretContent(m.selectedSecret.namespace, m.selectedSecret.name, m.showRaw)
-	m.certViewPages = data
-	m.inspectedError = err
-	m.certPaginator.SetTotalPages(len(data))
+func (m *Model) updateDashboard(keyStr string) tea.Cmd {
+	switch keyStr {
+	case "q":
+		return m.quit()
+	case "up", "k":
+		m.dashboard.CursorUp()
+	case "down", "j":
+		m.dashboard.CursorDown()
+	case "enter":
+		return m.showSecrets(m.dashboard.SelectedFilter())
+	case "d", "esc":
+		return m.showSecrets(m.listFilter)
+	case "u":
+		return func() tea.Msg { return loadSecretsMsg{} }
+	case "e":
+		return func() tea.Msg { return exportMsg{} }
+	case "n":
+		return loadPickerCmd(*m, namespacePicker)
+	case "x":
+		return loadPickerCmd(*m, contextPicker)
+	}
+	return nil
+}
+
+// openPicker shows the overlay with the loaded namespaces or contexts, the current one is
+// preselected.
+func (m *Model) openPicker(msg pickerLoadedMsg) {
+	previous := m.selectedPane
+	if previous == PickerPane {
+		previous = m.picker.previous
+	}
+	current := m.namespace
+	if msg.kind == contextPicker {
+		current = m.context
+	}
+
+	m.picker = newPickerModel(msg.kind, msg.options, current, previous)
+	m.picker.SetSize(m.pickerSize())
+	m.selectedPane = PickerPane
+	m.helpView.SetPane(m.selectedPane)
+}
+
+// switchTarget points the TUI at the picked namespace or context, restarts the live updates
+// and reloads the secrets.
+func (m *Model) switchTarget(msg pickerSelectedMsg) tea.Cmd {
+	switch msg.kind {
+	case contextPicker:
+		svc, err := m.switcher.Service(msg.option)
+		if err != nil {
+			return func() tea.Msg { return statusMsg{fmt.Sprintf("Can not switch to context %s: %v", msg.option, err)} }
+		}
+		m.secretsService = svc
+		m.context = msg.option
+	default:
+		m.namespace = msg.option
+	}
+
+	// a single secret lensed at startup does not exist in the new target
+	m.name = ""
+	m.secrets = nil
+	m.selectedSecret = nil
+	m.listFilter = nil
+	m.secretsList.ResetSelected()
+	m.secretsList.Title = m.listTitle()
+
+	m.stopWatch()
+	m.watchCtx, m.stopWatch = context.WithCancel(context.Background())
+	m.watchEvents = nil
+	m.pendingEvents = nil
+
+	return m.startListing()
+}
+
+// showSecrets leaves the dashboard for the list, narrowed down to the secrets matching filter.
+func (m *Model) showSecrets(filter *service.DashboardEntry) tea.Cmd {
+	m.listFilter = filter
+	m.selectedPane = LeftPane
+	m.helpView.SetPane(m.selectedPane)
+	m.secretsList.ResetSelected()
+	return m.refreshList()
+}
+
+// finishListing applies the events received while the listing ran.
+func (m *Model) finishListing() tea.Cmd {
+	m.listing = false
+	var cmds []tea.Cmd
+	for _, event := range m.pendingEvents {
+		cmds = append(cmds, m.applySecretEvent(event))
+	}
+	m.pendingEvents = nil
+	return tea.Batch(cmds...)
+}
+
+// applySecretEvent updates the list with a live change and marks the affected item as changed.
+func (m *Model) applySecretEvent(event service.TLSSecretEvent) tea.Cmd {
+	if m.name != "" && event.Summary.Name != m.name {
+		return nil
+	}
+
+	index := slices.IndexFunc(m.secrets, func(item secretItem) bool {
+		return item.summary.K8SResourceID == event.Summary.K8SResourceID
+	})
+
+	if event.Type == domains.SecretDeleted {
+		if index != -1 {
+			m.secrets = slices.Delete(m.secrets, index, index+1)
+		}
+		return m.refreshList()
+	}
+
+	item := newSecretItem(event.Summary, m.theme)
+	item.changed = true
+	if index == -1 {
+		m.secrets = append(m.secrets, item)
+		return m.refreshList()
+	}
+
+	m.secrets[index] = item
+	cmd := m.refreshList()
+	if m.selectedSecret != nil && m.selectedSecret.summary.K8SResourceID == item.summary.K8SResourceID {
+		// the inspected secret was rotated, refresh the right pane
+		return tea.Batch(cmd, func() tea.Msg { return inspectTLSSecretMsg{tag: m.debounceTag} })
+	}
+	return cmd
+}
+
+// applySecretsPage adds a streamed page to the list, the first page replaces the secrets of
+// the previous listing. A listing failing before its first page keeps the previous secrets.
+func (m *Model) applySecretsPage(msg secretsPageMsg) tea.Cmd {
+	m.loading = false
+	if msg.err != nil {
+		m.reportError(fmt.Sprintf("Error: failed to load secretsList in namespace %s", m.namespace), msg.err)
+		if m.loadedPages == 0 {
+			return nil
+		}
+	}
+
+	if m.loadedPages == 0 {
+		m.secrets = nil
+	}
+	m.loadedPages++
+	m.secrets = append(m.secrets, msg.items...)
+
+	var partial *domains.PartialError
+	switch {
+	case errors.As(msg.err, &partial):
+		m.helpView.SetStatus(fmt.Sprintf("Listed %d secrets, some sources failed: %v", len(m.secrets), partial))
+	case msg.err != nil && !timedOut(msg.err):
+		m.helpView.SetStatus(fmt.Sprintf("Listed %d secrets before failing", len(m.secrets)))
+	case msg.err != nil:
+		m.helpView.SetStatus(fmt.Sprintf("Listed %d secrets before timing out, press u to retry", len(m.secrets)))
+	case msg.done:
+		m.helpView.SetStatus(fmt.Sprintf("Listed %d secrets", len(m.secrets)))
+	default:
+		m.helpView.SetStatus(fmt.Sprintf("Listing secrets... %d so far", len(m.secrets)))
+	}
+	return m.refreshList()
+}
+
+// refreshList rebuilds the list and the dashboard from all secrets, applying the dashboard
+// filter and the sort mode.
+func (m *Model) refreshList() tea.Cmd {
+	summaries := make([]service.TLSSecretSummary, 0, len(m.secrets))
+	var items []list.Item
+	for _, item := range m.secrets {
+		summaries = append(summaries, item.summary)
+		if m.listFilter == nil || m.listFilter.Match(item.summary) {
+			items = append(items, item)
+		}
+	}
+
+	m.dashboard.SetSummaries(summaries)
+	sortItems(items, m.sortMode)
+	m.secretsList.Title = m.listTitle()
+	return m.secretsList.SetItems(items)
+}
+
+func (m Model) listTitle() string {
+	title := "Select a TLS Secret (sort: " + m.sortMode.String()
+	if m.listFilter != nil {
+		title += ", filter: " + m.listFilter.Label
+	}
+	if m.namespace != "" {
+		title += ", namespace: " + m.namespace
+	}
+	if m.context != "" {
+		title += ", context: " + m.context
+	}
+	return title + ")"
+}
+
+// applyInspection shows the pages of a finished inspection, inspections cancelled by a newer
+// one are dropped. The secret lensed with -probe is then compared with the live endpoint.
+func (m *Model) applyInspection(msg inspectedTLSSecretMsg) tea.Cmd {
+	if errors.Is(msg.err, context.Canceled) {
+		return nil
+	}
+	if timedOut(msg.err) {
+		m.helpView.SetStatus("Inspection timed out, press i to retry")
+	}
+
+	m.certViewPages = msg.pages
+	m.certViewCerts = msg.certs
+	m.inspectedError = msg.err
+	if msg.err != nil {
+		return nil
+	}
+	m.certPaginator.SetTotalPages(len(msg.pages))
 	m.certPaginator.Page = 0
 	m.inspectedViewport.SetContent(m.certViewPages[m.certPaginator.Page] + "\n\n" + m.certPaginator.View())
+
+	if !m.probes(m.selectedSecret) || m.showRaw {
+		return nil
+	}
+	m.helpView.SetStatus("Probing " + m.probeTarget + "...")
+	var ctx context.Context
+	ctx, m.cancelInspect = context.WithCancel(context.Background())
+	return probeTLSSecretCmd(ctx, *m, msg.tag)
+}
+
+// probes reports whether the secret is the one the -probe target was configured for.
+func (m Model) probes(secret *secretItem) bool {
+	if m.probeTarget == "" || secret == nil || secret.summary.Kind != "" || secret.summary.Name != m.probeSecret.Name {
+		return false
+	}
+	return m.probeSecret.Namespace == "" || secret.summary.Namespace == m.probeSecret.Namespace
+}
+
+// applyProbe adds the live endpoint section to the first page and the served certificates as
+// extra pages of the inspection.
+func (m *Model) applyProbe(msg probedTLSSecretMsg) {
+	if errors.Is(msg.err, context.Canceled) || len(m.certViewPages) == 0 {
+		return
+	}
+	if msg.err != nil {
+		m.helpView.SetStatus("Probe failed, see the Live Endpoint section")
+	} else {
+		m.helpView.SetStatus(fmt.Sprintf("Probed %s: %s", m.probeTarget, msg.report.Status))
+	}
+
+	m.certViewPages[0] = formatProbeReport(m.probeTarget, msg.report, msg.err, m.theme) + m.certViewPages[0]
+	probeViews, probeCerts := probePages(msg.report, m.theme)
+	m.certViewPages = append(m.certViewPages, probeViews...)
+	m.certViewCerts = append(m.certViewCerts, probeCerts...)
+	m.certPaginator.SetTotalPages(len(m.certViewPages))
+	m.inspectedViewport.SetContent(m.certViewPages[m.certPaginator.Page] + "\n\n" + m.certPaginator.View())
+}
+
+// reportError opens the error modal, which quits on the next key. Requests that timed out and
+// clusters failing while others answered are not fatal, they are only reported in the status line.
+func (m *Model) reportError(prefix string, err error) {
+	var partial *domains.PartialError
+	switch {
+	case timedOut(err):
+		m.helpView.SetStatus(prefix + ": the API server did not answer in time, press u to retry")
+		return
+	case errors.As(err, &partial):
+		m.helpView.SetStatus(fmt.Sprintf("%s: some sources failed: %v", prefix, partial))
+		return
+	}
+	m.errorModalMsg = fmt.Sprintf("%s: %v", prefix, err)
+}
+
+func timedOut(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err)
 }
 
 func (m Model) View() string {
@@ -234,43 +809,153 @@ func (m Model) View() string {
 		return m.renderErrorModal(m.errorModalMsg)
 	}
 
-	left := m.leftPane(m.uiLayout.LeftPaneWidth, m.uiLayout.UsableHeight)
-	right := m.rightPane(m.uiLayout.RightPaneWidth, m.uiLayout.UsableHeight)
-
-	mainContent := m.theme.DocStyle().Render(lipgloss.JoinHorizontal(lipgloss.Top, left, right))
+	var mainContent string
+	if m.selectedPane == PickerPane {
+		width, height := m.pickerSize()
+		picker := m.theme.Pane(true, width, height).Render(m.picker.View())
+		mainContent = lipgloss.Place(m.uiLayout.TotalWidth, m.uiLayout.UsableHeight, lipgloss.Center, lipgloss.Center, picker)
+	} else if m.selectedPane == DashboardPane {
+		mainContent = m.theme.DocStyle().Render(m.dashboardPane(m.uiLayout.UsableWidth-2, m.uiLayout.UsableHeight))
+	} else {
+		left := m.leftPane(m.uiLayout.LeftPaneWidth, m.uiLayout.UsableHeight)
+		right := m.rightPane(m.uiLayout.RightPaneWidth, m.uiLayout.UsableHeight)
+		mainContent = m.theme.DocStyle().Render(lipgloss.JoinHorizontal(lipgloss.Top, left, right))
+	}
 	helpContent := m.helpView.View()
 
 	return lipgloss.JoinVertical(lipgloss.Left, mainContent, helpContent)
 }
 
-func loadSecretsCmd(m Model) tea.Cmd {
-	return tea.Batch(
-		func() tea.Msg { return loadingStartedMsg{} },
-		func() tea.Msg {
-			if m.secretsService == nil {
-				return errorMsg{fmt.Errorf("secretsList service not initialized")}
+func loadSecretsCmd(ctx context.Context, m Model) tea.Cmd {
+	tag := m.loadTag
+	return func() tea.Msg {
+		if m.secretsService == nil {
+			return errorMsg{fmt.Errorf("secretsList service not initialized")}
+		}
+
+		if m.name != "" {
+			secret, err := m.secretsService.ListTLSSecret(ctx, m.namespace, m.name)
+			if err != nil {
+				return errorMsg{fmt.Errorf("failed to load secret %s/%s: %w", m.namespace, m.name, err)}
 			}
+			return secretsLoadedMsg{tag, []secretItem{newSecretItem(secret, m.theme)}}
+		}
 
-			if m.name != "" {
-				secret, err := m.secretsService.ListTLSSecret(m.namespace, m.name)
-				if err != nil {
-					return errorMsg{fmt.Errorf("failed to load secret %s/%s: %w", m.namespace, m.name, err)}
+		pages := make(chan secretsPageMsg)
+		go func() {
+			defer close(pages)
+			err := m.secretsService.ListTLSSecretsPages(ctx, m.namespace, func(page []service.TLSSecretSummary) error {
+				items := make([]secretItem, len(page))
+				for i, s := range page {
+					items[i] = newSecretItem(s, m.theme)
 				}
-				return secretsLoadedMsg{[]list.Item{secretItem{secret.Name, secret.Namespace}}}
-			}
+				pages <- secretsPageMsg{tag: tag, items: items}
+				return nil
+			})
+			pages <- secretsPageMsg{tag: tag, done: true, err: err}
+		}()
+		return waitForSecretsPageCmd(pages)()
+	}
+}
 
-			secrets, err := m.secretsService.ListTLSSecrets(m.namespace)
-			if err != nil {
-				return errorMsg{fmt.Errorf("failed to load secretsList in namespace %s: %w", m.namespace, err)}
-			}
+// waitForSecretsPageCmd reads the next page of a streamed listing.
+func waitForSecretsPageCmd(pages <-chan secretsPageMsg) tea.Cmd {
+	return func() tea.Msg {
+		msg := <-pages
+		msg.pages = pages
+		return msg
+	}
+}
+
+func startWatchCmd(m Model) tea.Cmd {
+	return func() tea.Msg {
+		events, err := m.secretsService.WatchTLSSecrets(m.watchCtx, m.namespace)
+		return watchStartedMsg{watchCtx: m.watchCtx, events: events, err: err}
+	}
+}
+
+func inspectTLSSecretCmd(ctx context.Context, m Model, tag int) tea.Cmd {
+	secret := *m.selectedSecret
+	return func() tea.Msg {
+		pages, certs, err := m.inspectedTLSSecretContent(ctx, secret.namespace, secret.ref, m.showRaw)
+		return inspectedTLSSecretMsg{tag: tag, pages: pages, certs: certs, err: err}
+	}
+}
+
+// copySecretCmd writes the certificate, or the private key, of the selected secret to the clipboard.
+func copySecretCmd(ctx context.Context, m Model, key bool) tea.Cmd {
+	secret := *m.selectedSecret
+	return func() tea.Msg {
+		tlsCert, tlsKey, err := m.secretsService.RawInspectTLSSecret(ctx, secret.namespace, secret.ref)
+		if err != nil {
+			return copiedMsg{err: err}
+		}
+		what, data := "certificate", tlsCert
+		if key {
+			what, data = "private key", tlsKey
+		}
+		return copiedMsg{what: what, err: clipboard.WriteAll(data)}
+	}
+}
 
-			items := make([]list.Item, len(secrets))
-			for i, s := range secrets {
-				items[i] = secretItem{s.Name, s.Namespace}
+func probeTLSSecretCmd(ctx context.Context, m Model, tag int) tea.Cmd {
+	secret := *m.selectedSecret
+	return func() tea.Msg {
+		report, err := m.secretsService.ProbeTLSSecret(ctx, secret.namespace, secret.ref, m.probeTarget)
+		return probedTLSSecretMsg{tag: tag, report: report, err: err}
+	}
+}
+
+func waitForSecretEventCmd(events <-chan service.TLSSecretEvent) tea.Cmd {
+	return func() tea.Msg {
+		event, ok := <-events
+		if !ok {
+			return nil
+		}
+		return secretEventMsg{event, events}
+	}
+}
+
+// exportInventoryCmd writes the inventory to a new file in the export directory, an export
+// cancelled by a newer one or by quitting reports nothing.
+func exportInventoryCmd(ctx context.Context, m Model, format export.Format) tea.Cmd {
+	return func() tea.Msg {
+		var inspections []service.TLSSecretInspection
+		var partial *domains.PartialError
+		var err error
+		if m.name != "" {
+			var inspection service.TLSSecretInspection
+			if inspection, err = m.secretsService.InspectTLSSecret(ctx, m.namespace, m.name); err == nil {
+				inspections = append(inspections, inspection)
 			}
-			return secretsLoadedMsg{items}
-		},
-	)
+		} else if inspections, err = m.secretsService.InspectTLSSecrets(ctx, m.namespace); errors.As(err, &partial) {
+			err = nil
+		}
+		switch {
+		case errors.Is(err, context.Canceled):
+			return nil
+		case err != nil:
+			return statusMsg{fmt.Sprintf("Export failed: %v", err)}
+		}
+
+		path := filepath.Join(m.exportDir, fmt.Sprintf("certlens-export-%s.%s", time.Now().Format("20060102-150405"), format))
+		file, err := os.Create(path)
+		if err != nil {
+			return statusMsg{fmt.Sprintf("Export failed: %v", err)}
+		}
+		if err := export.Write(file, format, inspections); err != nil {
+			_ = file.Close()
+			return statusMsg{fmt.Sprintf("Export failed: %v", err)}
+		}
+		if err := file.Close(); err != nil {
+			return statusMsg{fmt.Sprintf("Export failed: %v", err)}
+		}
+
+		if partial != nil {
+			return statusMsg{fmt.Sprintf("Exported %d secrets to %s, some sources failed: %v", len(inspections), path, partial)}
+		}
+		return statusMsg{fmt.Sprintf("Exported %d secrets to %s", len(inspections), path)}
+	}
 }
 
 func newSecretDelegate() secretDelegate {
@@ -291,6 +976,14 @@ func (m Model) leftPane(width, height int) string {
 	return style.Render(m.secretsList.View())
 }
 
+func (m Model) dashboardPane(width, height int) string {
+	style := m.theme.Pane(true, width, height)
+	if m.loading {
+		return style.Render(m.spinner.View() + " Loading secretsList...")
+	}
+	return style.Render(m.dashboard.View())
+}
+
 func (m Model) rightPane(width, height int) string {
 	style := m.theme.Pane(m.selectedPane == RightPane, width, height)
 	if m.inspectedError != nil {
@@ -314,25 +1007,89 @@ func nextPane(currentPane Pane) Pane {
 	return LeftPane
 }
 
-func (m Model) inspectedTLSSecretContent(namespace, name string, raw bool) ([]string, error) {
+// inspectedTLSSecretContent renders the pages of the detail pane and the certificate shown on
+// each page.
+func (m Model) inspectedTLSSecretContent(ctx context.Context, namespace, name string, raw bool) ([]string, []*service.CertificateInfo, error) {
 	if raw {
-		tlsCert, tlsKey, err := m.secretsService.RawInspectTLSSecret(namespace, name)
+		tlsCert, tlsKey, err := m.secretsService.RawInspectTLSSecret(ctx, namespace, name)
 		if err != nil {
-			return nil, fmt.Errorf("failed to inspect secret %s/%s: %w", namespace, name, err)
+			return nil, nil, fmt.Errorf("failed to inspect secret %s/%s: %w", namespace, name, err)
 		}
-		return []string{tlsCert, tlsKey}, nil
+		return []string{tlsCert, tlsKey}, make([]*service.CertificateInfo, 2), nil
 	}
 
-	certs, err := m.secretsService.InspectTLSSecret(namespace, name)
+	inspection, err := m.secretsService.InspectTLSSecret(ctx, namespace, name)
 	if err != nil {
-		return nil, fmt.Errorf("failed to inspect secret %s/%s: %w", namespace, name, err)
+		return nil, nil, fmt.Errorf("failed to inspect secret %s/%s: %w", namespace, name, err)
 	}
 
 	var views []string
-	for _, cert := range certs {
-		views = append(views, formatCertificateInfo(cert, m.theme))
+	var certs []*service.CertificateInfo
+	for i, cert := range inspection.Certificates {
+		certs = append(certs, &inspection.Certificates[i])
+		view := formatCertificateInfo(cert, m.theme)
+		if findings := findingsOf(inspection.Findings, i); len(findings) > 0 {
+			view = formatFindings(findings, m.theme) + view
+		}
+		if i == 0 {
+			if inspection.PrivateKey != nil {
+				view = formatPrivateKeyInfo(*inspection.PrivateKey, m.theme) + view
+			}
+			if inspection.UsedBy != nil || inspection.UsedByErr != nil {
+				view = formatUsedBy(inspection, m.theme) + view
+			}
+			if inspection.CertManager != nil {
+				view = formatSection("cert-manager", inspection.CertManager, m.theme) + view
+			} else if inspection.CertManagerErr != nil {
+				view = m.theme.Warning().Render("⚠ "+inspection.CertManagerErr.Error()) + "\n\n" + view
+			}
+			view = formatChainReport(inspection.Chain, m.theme) + view
+			if inspection.Kind != "" {
+				view = formatSource(inspection, m.theme) + view
+			}
+			if keyPair := cert.KeyMatches; keyPair != service.KeyPairMatch.String() && keyPair != service.KeyPairNotApplicable.String() {
+				view = m.theme.Warning().Render("⚠ Key Matches Certificate: "+cert.KeyMatches) + "\n\n" + view
+			}
+		}
+		views = append(views, view)
 	}
-	return views, nil
+
+	bundleViews, bundleCerts := bundlePages(inspection.Bundles, m.theme)
+	return append(views, bundleViews...), append(certs, bundleCerts...), nil
+}
+
+// openIdentifierPicker lists the fingerprints of the certificate on the current page to copy one.
+func (m *Model) openIdentifierPicker() {
+	page := m.certPaginator.Page
+	if page >= len(m.certViewCerts) || m.certViewCerts[page] == nil {
+		m.helpView.SetStatus("No certificate on this page")
+		return
+	}
+
+	m.picker = newIdentifierPickerModel(viewFieldsFromStruct(m.certViewCerts[page].CertificateIdentifiers), m.selectedPane)
+	m.picker.SetSize(m.pickerSize())
+	m.selectedPane = PickerPane
+	m.helpView.SetPane(m.selectedPane)
+}
+
+// openExportPicker lets the user pick the format the inventory is exported in.
+func (m *Model) openExportPicker() {
+	previous := m.selectedPane
+	if previous == PickerPane {
+		previous = m.picker.previous
+	}
+	m.picker = newExportPickerModel(export.Formats, m.exportFormat, m.exportDir, previous)
+	m.picker.SetSize(m.pickerSize())
+	m.selectedPane = PickerPane
+	m.helpView.SetPane(m.selectedPane)
+}
+
+func (m *Model) copyIdentifier(msg pickerSelectedMsg) {
+	if err := clipboard.WriteAll(msg.option); err != nil {
+		m.helpView.SetStatus(fmt.Sprintf("Can not copy %s: %v", msg.label, err))
+		return
+	}
+	m.helpView.SetStatus("Copied " + msg.label)
 }
 
 func (m *Model) updateLayout(width, height int) {
@@ -341,4 +1098,9 @@ func (m *Model) updateLayout(width, height int) {
 	m.inspectedViewport.Width = m.uiLayout.RightPaneWidth
 	m.inspectedViewport.Height = m.uiLayout.UsableHeight
 	m.helpView.SetWidth(m.uiLayout.TotalWidth)
+	m.picker.SetSize(m.pickerSize())
+}
+
+func (m Model) pickerSize() (width, height int) {
+	return min(60, m.uiLayout.UsableWidth-4), m.uiLayout.UsableHeight * 2 / 3
 }